@@ -20,14 +20,33 @@ func main() {
 				Usage:   "show help",
 				Aliases: []string{"h"},
 			},
+			&cli.StringFlag{
+				Name:    "cwd",
+				Usage:   "run as if eel was started in `DIR`",
+				Aliases: []string{"C"},
+			},
+		},
+		Before: func(c context.Context, cmd *cli.Command) (context.Context, error) {
+			if dir := cmd.String("cwd"); dir != "" {
+				if err := os.Chdir(dir); err != nil {
+					return c, fmt.Errorf("cannot change to %s: %v", dir, err)
+				}
+			}
+			return c, nil
 		},
 		Commands: []*cli.Command{
 			commands.CreateCommand(),
+			commands.InitCommand(),
 			commands.InstallCommand(),
+			commands.VendorCommand(),
 			commands.WebCommand(),
 			commands.PyCommand(),
 			commands.DevCommand(),
 			commands.BuildCommand(),
+			commands.RunCommand(),
+			commands.LintCommand(),
+			commands.FmtCommand(),
+			commands.TestCommand(),
 		},
 		Action: func(c context.Context, cmd *cli.Command) error {
 			fmt.Println("🐍 eel-cli: Use --help or -h to see available commands")
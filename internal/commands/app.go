@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"eel-cli/internal/config"
+)
+
+var appModes = []string{"chrome", "edge", "default", "none"}
+
+func isValidAppMode(mode string) bool {
+	if mode == "" {
+		return true
+	}
+	for _, m := range appModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// appEnv validates the app section and turns it into the EEL_APP_*
+// variables read by the generated main.py.
+func appEnv(app config.AppConfig) ([]string, error) {
+	var env []string
+
+	if !isValidAppMode(app.Mode) {
+		return nil, fmt.Errorf("invalid app mode: %s. Supported: %s", app.Mode, strings.Join(appModes, ", "))
+	}
+	if app.Mode != "" {
+		env = append(env, "EEL_APP_MODE="+app.Mode)
+	}
+
+	if app.Size != "" {
+		width, height, err := parseWindowSize(app.Size)
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, fmt.Sprintf("EEL_APP_SIZE=%d,%d", width, height))
+	}
+
+	if app.Position != "" {
+		x, y, err := parseWindowPosition(app.Position)
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, fmt.Sprintf("EEL_APP_POSITION=%d,%d", x, y))
+	}
+
+	if app.Port < 0 || app.Port > 65535 {
+		return nil, fmt.Errorf("invalid app port: %d", app.Port)
+	}
+	if app.Port != 0 {
+		env = append(env, "EEL_APP_PORT="+strconv.Itoa(app.Port))
+	}
+
+	if app.Host != "" {
+		env = append(env, "EEL_APP_HOST="+app.Host)
+	}
+
+	return env, nil
+}
+
+func parseWindowPosition(value string) (int, int, error) {
+	xs, ys, ok := strings.Cut(value, ",")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid window position: %s. Expected X,Y", value)
+	}
+
+	x, err := strconv.Atoi(strings.TrimSpace(xs))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid window position: %s. Expected X,Y", value)
+	}
+	y, err := strconv.Atoi(strings.TrimSpace(ys))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid window position: %s. Expected X,Y", value)
+	}
+
+	return x, y, nil
+}
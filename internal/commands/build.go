@@ -2,12 +2,14 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"eel-cli/internal/config"
+	"eel-cli/internal/pm"
 	"eel-cli/pkg/utils"
 
 	"github.com/urfave/cli/v3"
@@ -38,35 +40,46 @@ func BuildCommand() *cli.Command {
 				Usage:   "Create single executable file",
 				Aliases: []string{"of"},
 			},
+			&cli.BoolFlag{
+				Name:  "frozen",
+				Usage: "Install from the lockfiles only and fail if they are out of date (default when CI is set)",
+			},
+			&cli.BoolFlag{
+				Name:  "offline",
+				Usage: "Install build dependencies without network access, from the caches filled by eel vendor",
+			},
+			&cli.StringSliceFlag{
+				Name:  "bake-env",
+				Usage: "Bake the given variables from .env/.env.production into the app",
+			},
 		},
 		Action: func(c context.Context, cmd *cli.Command) error {
 			appName := cmd.String("name")
 			icon := cmd.String("icon")
 			noConsole := cmd.Bool("no-console")
 			oneFile := cmd.Bool("onefile")
-
-			return buildApplication(appName, icon, noConsole, oneFile)
+			install := installOptions{
+				Frozen:  cmd.Bool("frozen"),
+				Offline: cmd.Bool("offline"),
+			}
+			if !cmd.IsSet("frozen") {
+				install.Frozen = isCI()
+			}
+
+			return buildApplication(appName, icon, noConsole, oneFile, install, cmd.StringSlice("bake-env"))
 		},
 	}
 }
 
-func buildApplication(appName, icon string, noConsole, oneFile bool) error {
+func buildApplication(appName, icon string, noConsole, oneFile bool, install installOptions, bakeEnv []string) error {
 	logger := utils.NewLogger()
 	executor := utils.NewExecutor()
 
-	projectDir, err := executor.GetWorkingDir()
+	p, err := loadEelProject()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %v", err)
-	}
-
-	cfg, err := config.LoadConfig(projectDir)
-	if err != nil {
-		return fmt.Errorf("failed to load config: %v", err)
-	}
-
-	if !executor.FileExists(filepath.Join(projectDir, "main.py")) {
-		return fmt.Errorf("not in an Eel project directory (main.py not found)")
+		return err
 	}
+	projectDir, cfg := p.Dir, p.Config
 
 	if appName == "" {
 		appName = cfg.Build.AppName
@@ -86,15 +99,38 @@ func buildApplication(appName, icon string, noConsole, oneFile bool) error {
 		return fmt.Errorf("uv is not installed. Please install it first")
 	}
 
+	env, err := p.Env("production")
+	if err != nil {
+		return err
+	}
+
+	// The app section is baked in as well: a frozen app has no CLI to pass
+	// it at startup.
+	appVars, err := appEnv(cfg.App)
+	if err != nil {
+		return err
+	}
+	env = append(env, appVars...)
+
 	logger.Info("Building application: %s", appName)
 
-	logger.Info("Installing build dependencies...")
+	if install.Offline {
+		if install.UVCache, install.WebCache, err = p.VendorDirs(); err != nil {
+			return err
+		}
+	}
+
+	if install.Frozen {
+		logger.Info("Installing build dependencies from lockfiles...")
+	} else {
+		logger.Info("Installing build dependencies...")
+	}
 	ctx := context.Background()
-	if err := executor.RunCommand(ctx, projectDir, "uv", "sync", "--extra", "build"); err != nil {
+	if err := syncPythonDependencies(ctx, executor, projectDir, install, "--extra", "build"); err != nil {
 		return fmt.Errorf("failed to install build dependencies: %v", err)
 	}
 
-	distDir := filepath.Join(projectDir, "dist")
+	distDir := p.DistDir()
 	buildDir := filepath.Join(projectDir, "build")
 
 	if executor.DirExists(distDir) {
@@ -105,10 +141,16 @@ func buildApplication(appName, icon string, noConsole, oneFile bool) error {
 		os.RemoveAll(buildDir)
 	}
 
-	webDir := filepath.Join(projectDir, "web")
-	if executor.DirExists(webDir) {
+	webDir := p.WebDir()
+	if executor.DirExists(webDir) && !p.HasWebBuild() {
+		logger.Info("No package.json in %s, bundling it as it is", cfg.Paths.WebDir)
+	} else if executor.DirExists(webDir) {
 		logger.Info("Building web assets...")
-		if err := buildWebAssets(webDir, cfg.Manager); err != nil {
+		manager, err := p.PackageManager()
+		if err != nil {
+			return err
+		}
+		if err := buildWebAssets(webDir, manager, install, env); err != nil {
 			return fmt.Errorf("failed to build web assets: %v", err)
 		}
 	}
@@ -123,6 +165,7 @@ func buildApplication(appName, icon string, noConsole, oneFile bool) error {
 	}
 
 	args = append(args, "--paths", projectDir)
+	args = append(args, "--distpath", distDir)
 
 	if oneFile {
 		args = append(args, "--onefile")
@@ -138,16 +181,32 @@ func buildApplication(appName, icon string, noConsole, oneFile bool) error {
 	}
 
 	if executor.DirExists(webDir) {
-		args = append(args, "--add-data", ".distweb;.distweb")
+		if !executor.DirExists(p.WebOutDir()) {
+			return fmt.Errorf("web assets not found in %s - set paths.webOutDir in %s", cfg.Paths.WebOutDir, config.FileName)
+		}
+		// main.py looks for the assets in .distweb, wherever webOutDir is.
+		args = append(args, "--add-data", p.WebOutDir()+string(os.PathListSeparator)+bundledWebDir)
+	}
+
+	bakeEnv = append(append([]string(nil), cfg.Build.BakeEnv...), bakeEnv...)
+	for _, kv := range appVars {
+		name, _, _ := strings.Cut(kv, "=")
+		bakeEnv = append(bakeEnv, name)
+	}
+	if len(bakeEnv) > 0 {
+		moduleDir, err := writeBakedEnvModule(projectDir, bakeEnv, env)
+		if err != nil {
+			return fmt.Errorf("failed to generate %s: %v", bakedEnvModule, err)
+		}
+		logger.Info("Baking environment variables into %s", bakedEnvModule)
+		args = append(args, "--paths", moduleDir, "--hidden-import", bakedEnvModule)
 	}
 
-	mainPath := "main.py"
-	mainPath = ".\\main.py"
-	args = append(args, mainPath)
+	args = append(args, p.EntryPath())
 
 	logger.Info("Running PyInstaller with args: %s", strings.Join(args, " "))
 
-	if err := executor.RunCommand(ctx, projectDir, "uv", args...); err != nil {
+	if err := executor.RunCommandEnv(ctx, projectDir, env, "uv", args...); err != nil {
 		return fmt.Errorf("failed to build application: %v", err)
 	}
 
@@ -169,7 +228,9 @@ func buildApplication(appName, icon string, noConsole, oneFile bool) error {
 	return nil
 }
 
-func buildWebAssets(webDir, manager string) error {
+// buildWebAssets installs missing web dependencies and runs the build script.
+// Frozen builds always reinstall from the lockfile so a stale one fails here.
+func buildWebAssets(webDir string, manager pm.PackageManager, install installOptions, env []string) error {
 	executor := utils.NewExecutor()
 	ctx := context.Background()
 
@@ -177,19 +238,67 @@ func buildWebAssets(webDir, manager string) error {
 		return fmt.Errorf("package.json not found in web directory")
 	}
 
-	if !executor.DirExists(filepath.Join(webDir, "node_modules")) {
-		if err := installWebDependencies(webDir, manager); err != nil {
+	if install.Frozen || !executor.DirExists(filepath.Join(webDir, "node_modules")) {
+		if err := installWebDependencies(ctx, executor, webDir, manager, install); err != nil {
 			return fmt.Errorf("failed to install web dependencies: %v", err)
 		}
 	}
 
-	var args []string
-	switch manager {
-	case "npm", "yarn", "pnpm", "bun":
-		args = []string{"run", "build"}
-	default:
-		return fmt.Errorf("unsupported package manager: %s", manager)
+	return executor.RunCommandEnv(ctx, webDir, env, manager.Name(), manager.Run("build")...)
+}
+
+// bundledWebDir is where the frozen app finds the web assets, relative to
+// main.py.
+const bundledWebDir = ".distweb"
+
+// isCI reports whether the CI variable set by most CI services is present.
+func isCI() bool {
+	switch strings.ToLower(os.Getenv("CI")) {
+	case "", "0", "false":
+		return false
+	}
+	return true
+}
+
+const bakedEnvModule = "eel_build_env"
+
+// writeBakedEnvModule generates a Python module that seeds os.environ with
+// the selected build-time values. The template main.py imports it when present.
+func writeBakedEnvModule(projectDir string, names []string, env []string) (string, error) {
+	logger := utils.NewLogger()
+
+	lookup := map[string]string{}
+	for _, kv := range env {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			lookup[key] = value
+		}
+	}
+
+	values := map[string]string{}
+	for _, name := range names {
+		value, ok := lookup[name]
+		if !ok {
+			logger.Warning("Variable %s is not set, not baking it", name)
+			continue
+		}
+		values[name] = value
+	}
+
+	literal, err := json.MarshalIndent(values, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	moduleDir := filepath.Join(projectDir, ".eel", "generated")
+	if err := os.MkdirAll(moduleDir, 0755); err != nil {
+		return "", err
 	}
 
-	return executor.RunCommand(ctx, webDir, manager, args...)
+	source := "# Generated by eel build. Do not edit.\n" +
+		"import os\n\n" +
+		"VALUES: dict[str, str] = " + string(literal) + "\n\n" +
+		"for _key, _value in VALUES.items():\n" +
+		"    os.environ.setdefault(_key, _value)\n"
+
+	return moduleDir, os.WriteFile(filepath.Join(moduleDir, bakedEnvModule+".py"), []byte(source), 0644)
 }
@@ -1,14 +1,19 @@
 package commands
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"eel-cli/internal/config"
+	"eel-cli/internal/pm"
 	"eel-cli/internal/template"
+	"eel-cli/internal/vite"
 	"eel-cli/pkg/utils"
 
 	"github.com/urfave/cli/v3"
@@ -29,6 +34,28 @@ func CreateCommand() *cli.Command {
 				Usage:   "Command to run in web directory after creation",
 				Aliases: []string{"i"},
 			},
+			&cli.StringFlag{
+				Name:  "python",
+				Usage: "Minimum Python version for the project",
+				Value: "3.10",
+			},
+			&cli.StringFlag{
+				Name:  "size",
+				Usage: "Initial window size (WIDTHxHEIGHT)",
+				Value: "1000x700",
+			},
+			&cli.StringFlag{
+				Name:  "from",
+				Usage: "Project template: local directory or git URL (use #ref to pick a branch or tag)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "var",
+				Usage: "Template variable as key=value (skips the prompt for that variable)",
+			},
+			&cli.BoolFlag{
+				Name:  "allow-hooks",
+				Usage: "Run the post-create hooks of a git template without asking",
+			},
 		},
 		Action: func(c context.Context, cmd *cli.Command) error {
 			args := cmd.Args().Slice()
@@ -57,7 +84,33 @@ func CreateCommand() *cli.Command {
 				return fmt.Errorf("invalid package manager: %s. Supported: npm, yarn, pnpm, bun", manager)
 			}
 
-			return createProject(projectName, manager, selectedTemplate)
+			data := template.NewData(projectName, manager)
+			pythonVersion, err := template.ParsePythonVersion(cmd.String("python"))
+			if err != nil {
+				return err
+			}
+			data.PythonVersion = pythonVersion
+			width, height, err := parseWindowSize(cmd.String("size"))
+			if err != nil {
+				return err
+			}
+			data.WindowWidth = width
+			data.WindowHeight = height
+
+			source := template.Default()
+			if from := cmd.String("from"); from != "" {
+				source, err = template.Resolve(c, from)
+				if err != nil {
+					return err
+				}
+				defer source.Close()
+			}
+
+			if err := resolveTemplateVars(source, cmd.StringSlice("var"), data.Vars); err != nil {
+				return err
+			}
+
+			return createProject(projectName, manager, selectedTemplate, source, data, cmd.Bool("allow-hooks"))
 		},
 	}
 }
@@ -104,17 +157,88 @@ func parseMultiWordFlag(argv []string, long string, short string) string {
 	return ""
 }
 
-func isValidManager(manager string) bool {
-	validManagers := []string{"npm", "yarn", "pnpm", "bun"}
-	for _, valid := range validManagers {
-		if manager == valid {
-			return true
+func parseWindowSize(value string) (int, int, error) {
+	parts := strings.Split(strings.ToLower(value), "x")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid window size: %s. Expected WIDTHxHEIGHT", value)
+	}
+
+	width, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || width <= 0 {
+		return 0, 0, fmt.Errorf("invalid window width: %s", parts[0])
+	}
+	height, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil || height <= 0 {
+		return 0, 0, fmt.Errorf("invalid window height: %s", parts[1])
+	}
+
+	return width, height, nil
+}
+
+func resolveTemplateVars(source *template.Source, overrides []string, vars map[string]string) error {
+	for _, raw := range overrides {
+		key, value, ok := strings.Cut(raw, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid template variable: %s. Expected key=value", raw)
+		}
+		vars[strings.TrimSpace(key)] = value
+	}
+
+	// Without a terminal to ask on (CI, piped input), every variable that
+	// --var did not set takes its default.
+	interactive := utils.IsTerminal(os.Stdin)
+
+	reader := bufio.NewReader(os.Stdin)
+	for _, v := range source.Manifest.Variables {
+		if _, ok := vars[v.Name]; ok {
+			continue
+		}
+
+		if v.Prompt == "" || !interactive {
+			vars[v.Name] = v.Default
+			continue
+		}
+
+		if v.Default != "" {
+			fmt.Printf("🐍 eel-cli: %s (%s): ", v.Prompt, v.Default)
+		} else {
+			fmt.Printf("🐍 eel-cli: %s: ", v.Prompt)
+		}
+
+		input, err := reader.ReadString('\n')
+		if err != nil && input == "" {
+			return fmt.Errorf("failed to read value for %s: %v", v.Name, err)
+		}
+
+		input = strings.TrimSpace(input)
+		if input == "" {
+			input = v.Default
 		}
+		vars[v.Name] = input
+	}
+
+	return nil
+}
+
+func gitAuthor() (string, string) {
+	executor := utils.NewExecutor()
+	ctx := context.Background()
+
+	if !executor.CommandExists("git") {
+		return "", ""
 	}
-	return false
+
+	name, _ := executor.RunCommandOutput(ctx, "", "git", "config", "user.name")
+	email, _ := executor.RunCommandOutput(ctx, "", "git", "config", "user.email")
+
+	return name, email
 }
 
-func createProject(projectName, manager, templateName string) error {
+func isValidManager(manager string) bool {
+	return pm.IsSupported(manager)
+}
+
+func createProject(projectName, manager, templateName string, source *template.Source, data template.Data, allowHooks bool) error {
 	logger := utils.NewLogger()
 	executor := utils.NewExecutor()
 
@@ -128,21 +252,28 @@ func createProject(projectName, manager, templateName string) error {
 		return fmt.Errorf("failed to create project directory: %v", err)
 	}
 
-	webDir := executor.JoinPath(projectName, "web")
-	if err := scaffoldWebWithVite(projectName, manager, templateName); err != nil {
+	paths := config.DefaultPaths()
+	if source.HasFile(paths.WebDir + "/package.json") {
+		logger.Info("Using web project from template %s", source.Name)
+	} else if err := scaffoldWebWithVite(projectName, paths.WebDir, manager, templateName); err != nil {
 		return fmt.Errorf("failed to scaffold web with Vite: %v", err)
 	}
 
-	if err := template.CopyTemplateFiles(projectName); err != nil {
+	data.Author, data.AuthorEmail = gitAuthor()
+	if err := template.CopyTemplateFiles(projectName, data); err != nil {
 		return fmt.Errorf("failed to copy template files: %v", err)
 	}
 
-	if err := ensureViteBuildConfig(webDir); err != nil {
-		logger.Warning("Could not update vite config: %v", err)
+	if source != template.Default() {
+		logger.Info("Applying template %s", source.Name)
+		if err := source.Copy(projectName, data); err != nil {
+			return fmt.Errorf("failed to copy template %s: %v", source.Name, err)
+		}
 	}
 
 	cfg := &config.Config{
 		Manager: manager,
+		Paths:   paths,
 		Dev: config.DevConfig{
 			Mode: "url",
 		},
@@ -154,9 +285,53 @@ func createProject(projectName, manager, templateName string) error {
 		},
 	}
 
+	if executor.FileExists(filepath.Join(projectName, config.FileName)) {
+		templateCfg, err := config.LoadConfig(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to load template config: %v", err)
+		}
+		if templateCfg.Manager == "" {
+			templateCfg.Manager = manager
+		}
+		if templateCfg.Build.AppName == "" {
+			templateCfg.Build.AppName = projectName
+		}
+		cfg = templateCfg
+	}
+
+	if err := ensureViteBuildConfig(projectName, cfg.Paths); err != nil {
+		logger.Warning("Could not update vite config: %v", err)
+	}
+	if err := ensureEelScript(filepath.Join(projectName, cfg.Paths.WebDir)); err != nil {
+		logger.Warning("Could not add eel.js to index.html: %v", err)
+	}
+
 	if err := config.SaveConfig(projectName, cfg); err != nil {
 		return fmt.Errorf("failed to save config: %v", err)
 	}
+	if err := ensureGitignore(projectName, cfg.Paths); err != nil {
+		return err
+	}
+
+	var hooks []string
+	for _, hook := range source.Manifest.Hooks.PostCreate {
+		command, err := source.RenderHook(hook, data)
+		if err != nil {
+			return fmt.Errorf("invalid post-create hook %q: %v", hook, err)
+		}
+		hooks = append(hooks, command)
+	}
+
+	runHooks := len(hooks) == 0 || !source.Remote || allowHooks || confirmHooks(source.Name, hooks)
+	if runHooks {
+		for _, command := range hooks {
+			if err := executor.RunShell(context.Background(), projectName, command); err != nil {
+				return fmt.Errorf("post-create hook failed: %v", err)
+			}
+		}
+	} else {
+		logger.Warning("Skipped the post-create hooks of %s", source.Name)
+	}
 
 	logger.Success("Project %s created successfully!", projectName)
 	logger.Info("Next steps:")
@@ -167,7 +342,32 @@ func createProject(projectName, manager, templateName string) error {
 	return nil
 }
 
-func scaffoldWebWithVite(projectDir, manager, templateName string) error {
+// confirmHooks shows the hooks of a remote template and asks before they
+// run. Without a terminal to ask on, they are skipped.
+func confirmHooks(name string, hooks []string) bool {
+	logger := utils.NewLogger()
+
+	logger.Info("Template %s wants to run:", name)
+	for _, command := range hooks {
+		logger.Info("  %s", command)
+	}
+
+	if !utils.IsTerminal(os.Stdin) {
+		logger.Warning("Not a terminal, pass --allow-hooks to run them")
+		return false
+	}
+
+	fmt.Print("🐍 eel-cli: Run these commands? [y/N]: ")
+	input, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && input == "" {
+		return false
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(input))
+	return answer == "y" || answer == "yes"
+}
+
+func scaffoldWebWithVite(projectDir, webDir, manager, templateName string) error {
 	executor := utils.NewExecutor()
 	ctx := context.Background()
 
@@ -176,60 +376,68 @@ func scaffoldWebWithVite(projectDir, manager, templateName string) error {
 		tmpl = "vanilla"
 	}
 
-	args := []string{"create", "vite", "web", "--template", tmpl, "--no-rolldown", "--interactive", "--no-immediate"}
+	args := []string{"create", "vite", filepath.ToSlash(webDir), "--template", tmpl, "--no-rolldown", "--interactive", "--no-immediate"}
 
 	return executor.RunCommand(ctx, projectDir, manager, args...)
 }
 
-func ensureViteBuildConfig(webDir string) error {
-	executor := utils.NewExecutor()
-	candidates := []string{
-		filepath.Join(webDir, "vite.config.ts"),
-		filepath.Join(webDir, "vite.config.js"),
-		filepath.Join(webDir, "vite.config.mts"),
-		filepath.Join(webDir, "vite.config.mjs"),
-		filepath.Join(webDir, "vite.config.cts"),
-		filepath.Join(webDir, "vite.config.cjs"),
-	}
+func ensureViteBuildConfig(projectDir string, paths config.PathsConfig) error {
+	logger := utils.NewLogger()
 
-	var cfgPath string
-	for _, p := range candidates {
-		if executor.FileExists(p) {
-			cfgPath = p
-			break
-		}
-	}
-	if cfgPath == "" {
-		return fmt.Errorf("vite config not found in %s", webDir)
+	webDir := filepath.Join(projectDir, paths.WebDir)
+	cfgPath, err := vite.FindConfig(webDir)
+	missing := errors.Is(err, vite.ErrConfigNotFound)
+	if missing {
+		cfgPath = filepath.Join(webDir, "vite.config.js")
+	} else if err != nil {
+		return err
 	}
 
-	data, err := os.ReadFile(cfgPath)
+	outDir := paths.WebOutDir
+	if !filepath.IsAbs(outDir) {
+		outDir = filepath.Join(projectDir, outDir)
+	}
+	relOutDir, err := filepath.Rel(webDir, outDir)
 	if err != nil {
 		return err
 	}
-	content := string(data)
 
-	if strings.Contains(content, "build:") && strings.Contains(content, "outDir:") {
-		return nil
+	opts := vite.Options{
+		OutDir:      filepath.ToSlash(relOutDir),
+		EmptyOutDir: true,
+		Base:        "./",
 	}
 
-	insertBlock := "build: {\n    outDir: '../.distweb',\n    emptyOutDir: true,\n  },"
+	// The generated plugin is an ES module; CommonJS configs can't import it.
+	if ext := filepath.Ext(cfgPath); ext == ".cjs" || ext == ".cts" {
+		logger.Warning("%s is CommonJS, not adding the eel Vite plugin", filepath.Base(cfgPath))
+	} else if err := writeVitePlugin(webDir, cfgPath); err != nil {
+		logger.Warning("Could not write %s: %v", vitePluginFile, err)
+	} else {
+		opts.Plugins = append(opts.Plugins, eelVitePlugin())
+	}
 
-	idx := strings.Index(content, "defineConfig(")
-	if idx >= 0 {
-		braceIdx := strings.Index(content[idx:], "{")
-		if braceIdx >= 0 {
-			pos := idx + braceIdx + 1
-			updated := content[:pos] + "\n  " + insertBlock + content[pos:]
-			return os.WriteFile(cfgPath, []byte(updated), 0644)
+	if missing {
+		src, err := vite.Patch(vite.DefaultConfig, opts)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(cfgPath, []byte(src), 0644); err != nil {
+			return err
 		}
+		logger.Success("Created %s", filepath.Base(cfgPath))
+		return nil
+	}
+
+	diff, err := vite.PatchFile(cfgPath, opts)
+	if err != nil {
+		return err
 	}
 
-	firstBrace := strings.Index(content, "{")
-	if firstBrace >= 0 {
-		updated := content[:firstBrace+1] + "\n  " + insertBlock + content[firstBrace+1:]
-		return os.WriteFile(cfgPath, []byte(updated), 0644)
+	if diff != "" {
+		logger.Info("Updated %s:", filepath.Base(cfgPath))
+		fmt.Print(diff)
 	}
 
-	return fmt.Errorf("could not inject build config into %s", cfgPath)
+	return nil
 }
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"eel-cli/pkg/utils"
+)
+
+const defaultDebugPort = 5678
+
+type debugOptions struct {
+	Port int
+	Wait bool
+}
+
+// debugpyArgs returns the arguments that run entry under debugpy. debugpy is
+// pulled in with `uv run --with` so projects don't need it as a dependency.
+func debugpyArgs(entry string, opts debugOptions) []string {
+	args := []string{
+		"run", "--with", "debugpy", "python", "-m", "debugpy",
+		"--listen", "127.0.0.1:" + strconv.Itoa(opts.Port),
+	}
+	if opts.Wait {
+		args = append(args, "--wait-for-client")
+	}
+	return append(args, entry)
+}
+
+type launchFile struct {
+	Version        string         `json:"version"`
+	Configurations []launchConfig `json:"configurations"`
+}
+
+// launchConfig has no pathMappings: the debugger attaches to a process on
+// the same machine, which reports the local paths.
+type launchConfig struct {
+	Name       string        `json:"name"`
+	Type       string        `json:"type"`
+	Request    string        `json:"request"`
+	Connect    launchConnect `json:"connect"`
+	JustMyCode bool          `json:"justMyCode"`
+}
+
+type launchConnect struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+// ensureLaunchConfig writes a VS Code attach configuration for the debug
+// port unless the project already has a .vscode/launch.json.
+func ensureLaunchConfig(projectDir string, port int) error {
+	logger := utils.NewLogger()
+	launchPath := filepath.Join(projectDir, ".vscode", "launch.json")
+
+	if utils.NewExecutor().FileExists(launchPath) {
+		return nil
+	}
+
+	launch := launchFile{
+		Version: "0.2.0",
+		Configurations: []launchConfig{{
+			Name:       "Eel: attach to eel dev --debug",
+			Type:       "debugpy",
+			Request:    "attach",
+			Connect:    launchConnect{Host: "127.0.0.1", Port: port},
+			JustMyCode: true,
+		}},
+	}
+
+	data, err := json.MarshalIndent(launch, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(launchPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(launchPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", launchPath, err)
+	}
+
+	logger.Success("Created .vscode/launch.json (attach on port %d)", port)
+	return nil
+}
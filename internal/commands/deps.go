@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v3"
+)
+
+// dependency is one row of `eel web|py list|outdated`, normalized across
+// package managers.
+type dependency struct {
+	Name string `json:"name"`
+	// Current is the installed (web) or locked (Python) version.
+	Current string `json:"current"`
+	// Wanted is the newest version the declared range allows when the tool
+	// reports it, otherwise the declared range itself.
+	Wanted string `json:"wanted"`
+	Latest string `json:"latest"`
+	// Type is "prod" or "dev".
+	Type string `json:"type"`
+}
+
+func jsonFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:  "json",
+		Usage: "Print the result as JSON",
+	}
+}
+
+func printDependencies(deps []dependency, asJSON bool, empty string) error {
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Type != deps[j].Type {
+			return deps[i].Type == "prod"
+		}
+		return deps[i].Name < deps[j].Name
+	})
+
+	if asJSON {
+		if deps == nil {
+			deps = []dependency{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(deps)
+	}
+
+	if len(deps) == 0 {
+		fmt.Println(empty)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCURRENT\tWANTED\tLATEST\tTYPE")
+	for _, d := range deps {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", d.Name, orDash(d.Current), orDash(d.Wanted), orDash(d.Latest), d.Type)
+	}
+	return w.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// outdatedOutput returns stdout of an "outdated" command. npm, yarn and pnpm
+// exit with status 1 when something is outdated, which is not an error here.
+func outdatedOutput(out string, err error) (string, error) {
+	var exitErr *exec.ExitError
+	if err == nil || (errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && out != "") {
+		return out, nil
+	}
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return "", fmt.Errorf("%v: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return "", err
+}
@@ -3,16 +3,21 @@ package commands
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"eel-cli/internal/config"
+	"eel-cli/internal/devproxy"
+	"eel-cli/internal/pm"
 	"eel-cli/pkg/utils"
 
 	"github.com/urfave/cli/v3"
@@ -29,6 +34,42 @@ func DevCommand() *cli.Command {
 				Aliases: []string{"m"},
 				Value:   "url",
 			},
+			&cli.StringSliceFlag{
+				Name:  "only",
+				Usage: "Only show output of the given processes (vite, py)",
+			},
+			&cli.BoolFlag{
+				Name:  "log-files",
+				Usage: "Also write each process' output to .eel/logs/<name>.log",
+			},
+			&cli.BoolFlag{
+				Name:  "no-color",
+				Usage: "Disable colored output prefixes",
+			},
+			&cli.StringFlag{
+				Name:  "restart",
+				Usage: "Restart policy for all processes (on-failure, always, never)",
+			},
+			&cli.DurationFlag{
+				Name:  "ready-timeout",
+				Usage: "How long to wait for the Vite dev server to become ready",
+			},
+			&cli.StringFlag{
+				Name:  "browser",
+				Usage: "Browser mode for the app window (chrome, edge, default, none)",
+			},
+			&cli.BoolFlag{
+				Name:  "debug",
+				Usage: "Run the Python backend under debugpy",
+			},
+			&cli.IntFlag{
+				Name:  "debug-port",
+				Usage: "Port debugpy listens on (implies --debug)",
+			},
+			&cli.BoolFlag{
+				Name:  "wait-for-client",
+				Usage: "Wait for a debugger to attach before running main.py (implies --debug)",
+			},
 		},
 		Action: func(c context.Context, cmd *cli.Command) error {
 			mode := cmd.String("mode")
@@ -37,193 +78,459 @@ func DevCommand() *cli.Command {
 				return fmt.Errorf("invalid mode: %s. Supported modes: watch, url", mode)
 			}
 
-			return startDevServer(mode)
+			for _, name := range cmd.StringSlice("only") {
+				if name != "vite" && name != "py" {
+					return fmt.Errorf("invalid process name: %s. Supported: vite, py", name)
+				}
+			}
+
+			browser := cmd.String("browser")
+			if browser != "" && !isValidAppMode(browser) {
+				return fmt.Errorf("invalid browser mode: %s. Supported: %s", browser, strings.Join(appModes, ", "))
+			}
+
+			restart := cmd.String("restart")
+			if !isValidRestartPolicy(restart) {
+				return fmt.Errorf("invalid restart policy: %s. Supported: on-failure, always, never", restart)
+			}
+
+			return startDevServer(devOptions{
+				Mode:     mode,
+				Only:     cmd.StringSlice("only"),
+				LogFiles: cmd.Bool("log-files"),
+				NoColor:  cmd.Bool("no-color"),
+				Restart:  restart,
+				Browser:  browser,
+				Timeout:  cmd.Duration("ready-timeout"),
+				Debug:    cmd.Bool("debug") || cmd.IsSet("debug-port") || cmd.Bool("wait-for-client"),
+				Debugger: debugOptions{
+					Port: int(cmd.Int("debug-port")),
+					Wait: cmd.Bool("wait-for-client"),
+				},
+			})
 		},
 	}
 }
 
-func startDevServer(mode string) error {
+const (
+	devShutdownGrace = 5 * time.Second
+	devReadyTimeout  = 15 * time.Second
+	// The dev proxy prefers a fixed port so the app keeps the same origin
+	// (and localStorage) between runs.
+	devProxyPort = 5190
+)
+
+var viteLocalURL = regexp.MustCompile(`Local:\s+(https?://\S+)`)
+
+type devOptions struct {
+	Mode     string
+	Only     []string
+	LogFiles bool
+	NoColor  bool
+	Restart  string
+	Browser  string
+	Timeout  time.Duration
+	Debug    bool
+	Debugger debugOptions
+}
+
+// devSession holds what the dev modes share while starting their processes.
+type devSession struct {
+	project      *project
+	manager      pm.PackageManager
+	mux          *utils.Multiplexer
+	sup          *supervisor
+	restart      config.RestartConfig
+	readyTimeout time.Duration
+	env          []string
+	appEnv       []string
+	debug        *debugOptions
+	proxy        *devproxy.Proxy
+	logger       *utils.Logger
+}
+
+func startDevServer(opts devOptions) error {
 	logger := utils.NewLogger()
-	executor := utils.NewExecutor()
+	mode := opts.Mode
 
-	projectDir, err := executor.GetWorkingDir()
+	p, err := loadEelProject()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %v", err)
+		return err
 	}
 
-	cfg, err := config.LoadConfig(projectDir)
+	if _, err := p.RequireWebDir(); err != nil {
+		return err
+	}
+
+	restart := p.Config.Dev.Restart
+	if opts.Restart != "" {
+		restart.Py, restart.Vite = opts.Restart, opts.Restart
+	}
+	for _, policy := range []string{restart.Py, restart.Vite} {
+		if !isValidRestartPolicy(policy) {
+			return fmt.Errorf("invalid restart policy in config: %s. Supported: on-failure, always, never", policy)
+		}
+	}
+
+	readyTimeout := opts.Timeout
+	if readyTimeout == 0 && p.Config.Dev.ReadyTimeout > 0 {
+		readyTimeout = time.Duration(p.Config.Dev.ReadyTimeout) * time.Second
+	}
+	if readyTimeout == 0 {
+		readyTimeout = devReadyTimeout
+	}
+
+	env, err := p.Env("development")
 	if err != nil {
-		return fmt.Errorf("failed to load config: %v", err)
+		return err
 	}
 
-	if !executor.FileExists(filepath.Join(projectDir, "main.py")) {
-		return fmt.Errorf("not in an Eel project directory (main.py not found)")
+	app := p.Config.App
+	if opts.Browser != "" {
+		app.Mode = opts.Browser
+	}
+	appVars, err := appEnv(app)
+	if err != nil {
+		return err
 	}
 
-	webDir := filepath.Join(projectDir, "web")
-	if !executor.DirExists(webDir) {
-		return fmt.Errorf("web directory not found")
+	var debug *debugOptions
+	if opts.Debug || p.Config.Dev.Debug.Enabled {
+		debug = &opts.Debugger
+		if debug.Port == 0 {
+			debug.Port = p.Config.Dev.Debug.Port
+		}
+		if debug.Port == 0 {
+			debug.Port = defaultDebugPort
+		}
+		debug.Wait = debug.Wait || p.Config.Dev.Debug.Wait
+
+		if err := ensureLaunchConfig(p.Dir, debug.Port); err != nil {
+			logger.Warning("%v", err)
+		}
 	}
 
-	manager := cfg.Manager
-	if manager == "" {
-		manager = detectPackageManager()
+	muxOpts := utils.MuxOptions{
+		Only:       opts.Only,
+		Color:      !opts.NoColor && os.Getenv("NO_COLOR") == "" && utils.IsTerminal(os.Stdout),
+		Timestamps: true,
+	}
+	if opts.LogFiles || p.Config.Dev.LogFiles {
+		muxOpts.LogDir = filepath.Join(p.Dir, ".eel", "logs")
 	}
+	mux := utils.NewMultiplexer(os.Stdout, muxOpts)
+	defer mux.Close()
 
 	logger.Info("Starting development server in %s mode", mode)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	sigChan := make(chan os.Signal, 1)
+	manager, err := p.PackageManager()
+	if err != nil {
+		return err
+	}
+
+	procs := &utils.ProcessSet{}
+	session := &devSession{
+		project:      p,
+		manager:      manager,
+		mux:          mux,
+		sup:          newSupervisor(procs, logger),
+		restart:      restart,
+		readyTimeout: readyTimeout,
+		env:          env,
+		appEnv:       appVars,
+		debug:        debug,
+		logger:       logger,
+	}
+
+	sigChan := make(chan os.Signal, 2)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
 
 	go func() {
-		<-sigChan
+		if _, ok := <-sigChan; !ok {
+			return
+		}
 		logger.Info("Shutting down development server...")
 		cancel()
+
+		if _, ok := <-sigChan; ok {
+			logger.Warning("Forcing shutdown")
+			procs.KillAll()
+		}
 	}()
 
-	if mode == "url" {
-		return startURLMode(ctx, projectDir, webDir, manager, logger)
+	if !p.HasWebBuild() {
+		logger.Info("No package.json in %s, serving it as it is", p.Config.Paths.WebDir)
+		err = startEel(session, "EEL_WEB_ROOT="+p.WebOutDir())
+	} else if mode == "url" {
+		err = startURLMode(ctx, session)
 	} else {
-		return startWatchMode(ctx, projectDir, webDir, manager, logger)
+		err = startWatchMode(ctx, session)
+	}
+
+	if err == nil {
+		keys, restore := readKeys()
+		if keys != nil {
+			printDevKeys(logger)
+		}
+		session.sup.Run(ctx, keys)
+		restore()
 	}
+
+	procs.StopAll(devShutdownGrace)
+	if session.proxy != nil {
+		session.proxy.Close()
+	}
+	logger.Info("All processes stopped")
+
+	return err
 }
 
-func startURLMode(ctx context.Context, projectDir, webDir, manager string, logger *utils.Logger) error {
+func (s *devSession) ensureWebDependencies() error {
+	webDir := s.project.WebDir()
+
 	// Check if node_modules exists
 	if !utils.NewExecutor().DirExists(filepath.Join(webDir, "node_modules")) {
-		logger.Info("Installing web dependencies...")
-		if err := installWebDependencies(webDir, manager); err != nil {
+		s.logger.Info("Installing web dependencies...")
+		if err := installWebDependencies(context.Background(), utils.NewExecutor(), webDir, s.manager, installOptions{}); err != nil {
 			return fmt.Errorf("failed to install web dependencies: %v", err)
 		}
 	}
 
+	return nil
+}
+
+func startURLMode(ctx context.Context, s *devSession) error {
+	logger := s.logger
+	manager := s.manager
+	webDir := s.project.WebDir()
+
+	if err := s.ensureWebDependencies(); err != nil {
+		return err
+	}
+
 	vitePort := 5173
 	viteHost := "localhost"
-	viteURL := fmt.Sprintf("http://%s:%d", viteHost, vitePort)
-
-	logger.Info("Starting Vite dev server on %s", viteURL)
 
-	var viteArgs []string
-	switch manager {
-	case "bun":
-		viteArgs = []string{"run", "dev", "--port", strconv.Itoa(vitePort), "--host", viteHost}
-	case "npm", "yarn", "pnpm":
-		viteArgs = []string{"run", "dev", "--", "--port", strconv.Itoa(vitePort), "--host", viteHost}
-	default:
-		return fmt.Errorf("unsupported package manager: %s", manager)
+	// The Eel port is fixed up front so the generated Vite plugin can proxy
+	// to it as well (EEL_BACKEND_URL) for pages opened on Vite directly.
+	app := s.project.Config.App
+	eelHost := app.Host
+	if eelHost == "" {
+		eelHost = "localhost"
 	}
+	eelPort := app.Port
+	if eelPort == 0 {
+		var err error
+		if eelPort, err = freePort(); err != nil {
+			return fmt.Errorf("failed to find a free port for Eel: %v", err)
+		}
+	}
+	eelURL := fmt.Sprintf("http://%s:%d", eelHost, eelPort)
+	viteEnv := append(append([]string(nil), s.env...), "EEL_BACKEND_URL="+eelURL)
+
+	logger.Info("Starting Vite dev server (preferred port %d)", vitePort)
+
+	viteArgs := manager.Run("dev", "--port", strconv.Itoa(vitePort), "--host", viteHost)
 
-	viteCmd := exec.CommandContext(ctx, manager, viteArgs...)
-	viteCmd.Dir = webDir
-	viteCmd.Stdout = os.Stdout
-	viteCmd.Stderr = os.Stderr
+	viteOut, err := s.mux.Stream("vite")
+	if err != nil {
+		return err
+	}
 
-	if err := viteCmd.Start(); err != nil {
+	// Vite moves to the next free port when the preferred one is taken, so
+	// the URL it actually serves on is read from its "Local:" banner.
+	urls := make(chan string, 1)
+	viteOut.OnLine(func(line string) {
+		if m := viteLocalURL.FindStringSubmatch(line); m != nil {
+			select {
+			case urls <- strings.TrimSuffix(m[1], "/"):
+			default:
+			}
+		}
+	})
+
+	vite, err := s.sup.Start(&devProcess{
+		name:   "vite",
+		policy: s.restart.Vite,
+		command: func() (*exec.Cmd, error) {
+			viteCmd := exec.Command(manager.Name(), viteArgs...)
+			viteCmd.Dir = webDir
+			viteCmd.Env = viteEnv
+			viteCmd.Stdout = viteOut
+			viteCmd.Stderr = viteOut
+			return viteCmd, nil
+		},
+	})
+	if err != nil {
 		return fmt.Errorf("failed to start Vite: %v", err)
 	}
 
-	if err := waitForURL(viteURL, 15*time.Second); err != nil {
-		viteCmd.Process.Kill()
+	viteURL, err := waitForViteURL(ctx, urls, s.readyTimeout, vite)
+	if err != nil {
+		if tail := viteOut.Tail(20); len(tail) > 0 {
+			logger.Warning("Last output from vite:")
+			for _, line := range tail {
+				fmt.Fprintf(os.Stderr, "  %s\n", line)
+			}
+		}
 		return fmt.Errorf("Vite server failed to start: %v", err)
 	}
 
 	logger.Success("Vite dev server is ready at %s", viteURL)
 
-	os.Setenv("VITE_DEV_SERVER_URL", viteURL)
-
-	logger.Info("Starting Eel application...")
-	eelCmd := exec.CommandContext(ctx, "uv", "run", "python", "main.py")
-	eelCmd.Dir = projectDir
-	eelCmd.Stdout = os.Stdout
-	eelCmd.Stderr = os.Stderr
-
-	if err := eelCmd.Start(); err != nil {
-		viteCmd.Process.Kill()
-		return fmt.Errorf("failed to start Eel: %v", err)
+	proxyPort := s.project.Config.Dev.ProxyPort
+	if proxyPort == 0 {
+		proxyPort = devProxyPort
 	}
+	proxy, err := devproxy.Listen(fmt.Sprintf("127.0.0.1:%d", proxyPort), viteURL, eelURL)
+	if err != nil && s.project.Config.Dev.ProxyPort == 0 {
+		proxy, err = devproxy.Listen("127.0.0.1:0", viteURL, eelURL)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to start dev proxy: %v", err)
+	}
+	s.proxy = proxy
 
-	done := make(chan error, 2)
 	go func() {
-		done <- viteCmd.Wait()
+		if err := proxy.Serve(); err != nil {
+			logger.Error("Dev proxy stopped: %v", err)
+		}
 	}()
+
+	// Follow Vite to its new port when it is restarted.
 	go func() {
-		done <- eelCmd.Wait()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case url := <-urls:
+				if err := proxy.SetVite(url); err == nil {
+					logger.Info("Dev proxy now forwards to %s", url)
+				}
+			}
+		}
 	}()
 
-	err := <-done
-	if err != nil {
-		logger.Warning("Process exited with error: %v", err)
-	}
+	logger.Success("Dev server is ready at %s (Eel backend on port %d)", proxy.URL(), eelPort)
+	s.sup.browseURL = proxy.URL()
 
-	viteCmd.Process.Kill()
-	eelCmd.Process.Kill()
+	webRoot := filepath.Join(webDir, "src")
+	if !utils.NewExecutor().DirExists(webRoot) {
+		webRoot = webDir
+	}
 
-	return nil
+	return startEel(s,
+		"VITE_DEV_SERVER_URL="+viteURL,
+		"EEL_DEV_PROXY_PORT="+strconv.Itoa(proxy.Port()),
+		"EEL_DEV_WEB_ROOT="+webRoot,
+		"EEL_APP_PORT="+strconv.Itoa(eelPort),
+	)
 }
 
-func startWatchMode(ctx context.Context, projectDir, webDir, manager string, logger *utils.Logger) error {
-	if !utils.NewExecutor().DirExists(filepath.Join(webDir, "node_modules")) {
-		logger.Info("Installing web dependencies...")
-		if err := installWebDependencies(webDir, manager); err != nil {
-			return fmt.Errorf("failed to install web dependencies: %v", err)
-		}
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
 	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
 
-	logger.Info("Starting build watch...")
+func startEel(s *devSession, extraEnv ...string) error {
+	p := s.project
 
-	var watchArgs []string
-	switch manager {
-	case "bun":
-		watchArgs = []string{"run", "build", "--watch"}
-	case "npm", "yarn", "pnpm":
-		watchArgs = []string{"run", "build", "--", "--watch"}
-	default:
-		return fmt.Errorf("unsupported package manager: %s", manager)
+	s.logger.Info("Starting Eel application...")
+	pyOut, err := s.mux.Stream("py")
+	if err != nil {
+		return err
 	}
 
-	watchCmd := exec.CommandContext(ctx, manager, watchArgs...)
-	watchCmd.Dir = webDir
-	watchCmd.Stdout = os.Stdout
-	watchCmd.Stderr = os.Stderr
+	env := append(append(append([]string(nil), s.env...), s.appEnv...), extraEnv...)
 
-	if err := watchCmd.Start(); err != nil {
-		return fmt.Errorf("failed to start build watch: %v", err)
+	args := []string{"run", "python", p.EntryPath()}
+	if s.debug != nil {
+		args = debugpyArgs(p.EntryPath(), *s.debug)
+		if s.debug.Wait {
+			s.logger.Info("debugpy is waiting for a debugger on port %d", s.debug.Port)
+		} else {
+			s.logger.Info("debugpy is listening on port %d", s.debug.Port)
+		}
 	}
 
-	logger.Info("Starting Eel application...")
-	eelCmd := exec.CommandContext(ctx, "uv", "run", "python", "main.py")
-	eelCmd.Dir = projectDir
-	eelCmd.Stdout = os.Stdout
-	eelCmd.Stderr = os.Stderr
-
-	if err := eelCmd.Start(); err != nil {
-		watchCmd.Process.Kill()
+	if _, err := s.sup.Start(&devProcess{
+		name:   "py",
+		policy: s.restart.Py,
+		command: func() (*exec.Cmd, error) {
+			eelCmd := exec.Command("uv", args...)
+			eelCmd.Dir = p.Dir
+			eelCmd.Env = env
+			eelCmd.Stdout = pyOut
+			eelCmd.Stderr = pyOut
+			return eelCmd, nil
+		},
+	}); err != nil {
 		return fmt.Errorf("failed to start Eel: %v", err)
 	}
 
-	done := make(chan error, 2)
-	go func() {
-		done <- watchCmd.Wait()
-	}()
-	go func() {
-		done <- eelCmd.Wait()
-	}()
+	return nil
+}
+
+func startWatchMode(ctx context.Context, s *devSession) error {
+	logger := s.logger
+	manager := s.manager
+	webDir := s.project.WebDir()
+
+	if err := s.ensureWebDependencies(); err != nil {
+		return err
+	}
+
+	logger.Info("Starting build watch...")
+
+	watchArgs := manager.Run("build", "--watch")
 
-	err := <-done
+	watchOut, err := s.mux.Stream("vite")
 	if err != nil {
-		logger.Warning("Process exited with error: %v", err)
+		return err
 	}
 
-	watchCmd.Process.Kill()
-	eelCmd.Process.Kill()
+	if _, err := s.sup.Start(&devProcess{
+		name:   "vite",
+		policy: s.restart.Vite,
+		command: func() (*exec.Cmd, error) {
+			watchCmd := exec.Command(manager.Name(), watchArgs...)
+			watchCmd.Dir = webDir
+			watchCmd.Env = s.env
+			watchCmd.Stdout = watchOut
+			watchCmd.Stderr = watchOut
+			return watchCmd, nil
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to start build watch: %v", err)
+	}
 
-	return nil
+	return startEel(s, "EEL_WEB_ROOT="+s.project.WebOutDir())
 }
 
-func waitForURL(url string, timeout time.Duration) error {
+func waitForViteURL(ctx context.Context, urls <-chan string, timeout time.Duration, proc *utils.Process) (string, error) {
+	deadline := time.Now().Add(timeout)
+
+	select {
+	case url := <-urls:
+		return url, waitForURL(ctx, url, time.Until(deadline), proc)
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-proc.Done():
+		return "", fmt.Errorf("process exited before printing its URL")
+	case <-time.After(timeout):
+		return "", fmt.Errorf("no dev server URL reported within %s", timeout)
+	}
+}
+
+func waitForURL(ctx context.Context, url string, timeout time.Duration, proc *utils.Process) error {
 	client := &http.Client{Timeout: 3 * time.Second}
 	deadline := time.Now().Add(timeout)
 
@@ -236,7 +543,14 @@ func waitForURL(url string, timeout time.Duration) error {
 		if resp != nil {
 			resp.Body.Close()
 		}
-		time.Sleep(300 * time.Millisecond)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-proc.Done():
+			return fmt.Errorf("process exited before %s was reachable", url)
+		case <-time.After(300 * time.Millisecond):
+		}
 	}
 
 	return fmt.Errorf("timeout waiting for %s", url)
@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"eel-cli/internal/vite"
+	"eel-cli/pkg/utils"
+)
+
+const eelScriptTag = `<script type="text/javascript" src="/eel.js"></script>`
+
+const vitePluginFile = "vite-plugin-eel.js"
+
+// vitePluginSource is the Vite plugin generated next to vite.config. In dev
+// it proxies /eel.js and the /eel websocket to the backend for pages opened
+// on Vite's own port (eel dev's proxy handles them otherwise). In build it
+// keeps the classic eel.js tag out of Vite's bundling and puts it back
+// afterwards, so Eel serves it from the built app.
+const vitePluginSource = `// Generated by eel-cli. Wires Eel's /eel.js into Vite.
+const EEL_SCRIPT = /\s*<script\b[^>]*\bsrc=["']\/eel\.js["'][^>]*>\s*<\/script>/i;
+
+export default function eel(options = {}) {
+  const target = options.target ?? process.env.EEL_BACKEND_URL;
+
+  return [
+    {
+      name: 'eel:proxy',
+      apply: 'serve',
+      config() {
+        if (!target) return;
+        return {
+          server: {
+            proxy: {
+              '^/eel\\.js': { target },
+              '^/eel(\\?|$)': { target, ws: true },
+            },
+          },
+        };
+      },
+    },
+    {
+      name: 'eel:strip-script',
+      apply: 'build',
+      transformIndexHtml: {
+        order: 'pre',
+        handler: (html) => html.replace(EEL_SCRIPT, ''),
+      },
+    },
+    {
+      name: 'eel:inject-script',
+      apply: 'build',
+      transformIndexHtml: {
+        order: 'post',
+        handler: () => [
+          { tag: 'script', attrs: { type: 'text/javascript', src: '/eel.js' }, injectTo: 'head-prepend' },
+        ],
+      },
+    },
+  ];
+}
+`
+
+const vitePluginTypes = `// Generated by eel-cli.
+import type { PluginOption } from 'vite';
+
+export default function eel(options?: { target?: string }): PluginOption;
+`
+
+// ensureEelScript adds the eel.js script tag to the web index.html.
+func ensureEelScript(webDir string) error {
+	logger := utils.NewLogger()
+	indexPath := filepath.Join(webDir, "index.html")
+
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		return err
+	}
+
+	html := string(data)
+	if strings.Contains(html, "/eel.js") {
+		return nil
+	}
+
+	idx := strings.Index(strings.ToLower(html), "</head>")
+	if idx < 0 {
+		return fmt.Errorf("no </head> in %s", indexPath)
+	}
+
+	indent := lineIndent(html, idx)
+	tag := indent + "  " + eelScriptTag + "\n"
+	if strings.TrimSpace(html[strings.LastIndexByte(html[:idx], '\n')+1:idx]) != "" {
+		// </head> shares its line with other markup.
+		tag = eelScriptTag
+	} else {
+		idx = strings.LastIndexByte(html[:idx], '\n') + 1
+	}
+
+	if err := os.WriteFile(indexPath, []byte(html[:idx]+tag+html[idx:]), 0644); err != nil {
+		return err
+	}
+
+	logger.Success("Added eel.js to %s", filepath.Base(indexPath))
+	return nil
+}
+
+// writeVitePlugin writes the Vite plugin (and its types for TypeScript
+// configs) unless it already exists.
+func writeVitePlugin(webDir, configPath string) error {
+	executor := utils.NewExecutor()
+
+	pluginPath := filepath.Join(webDir, vitePluginFile)
+	if !executor.FileExists(pluginPath) {
+		if err := os.WriteFile(pluginPath, []byte(vitePluginSource), 0644); err != nil {
+			return err
+		}
+	}
+
+	ext := filepath.Ext(configPath)
+	if ext == ".ts" || ext == ".mts" {
+		typesPath := strings.TrimSuffix(pluginPath, ".js") + ".d.ts"
+		if !executor.FileExists(typesPath) {
+			if err := os.WriteFile(typesPath, []byte(vitePluginTypes), 0644); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func eelVitePlugin() vite.Plugin {
+	return vite.Plugin{Name: "eel", From: "./" + vitePluginFile}
+}
+
+func lineIndent(s string, offset int) string {
+	line := s[strings.LastIndexByte(s[:offset], '\n')+1:]
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
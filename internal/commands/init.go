@@ -0,0 +1,320 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"eel-cli/internal/config"
+	"eel-cli/internal/pm"
+	"eel-cli/internal/template"
+	"eel-cli/pkg/utils"
+
+	"github.com/urfave/cli/v3"
+)
+
+func InitCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "init",
+		Usage: "Adopt an existing Eel project in the current directory",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "manager",
+				Usage:   "Package manager (npm, yarn, pnpm, bun). Detected from lockfiles when omitted",
+				Aliases: []string{"pm"},
+			},
+		},
+		Action: func(c context.Context, cmd *cli.Command) error {
+			manager := cmd.String("manager")
+			if manager != "" && !isValidManager(manager) {
+				return fmt.Errorf("invalid package manager: %s. Supported: npm, yarn, pnpm, bun", manager)
+			}
+
+			return initProject(manager)
+		},
+	}
+}
+
+func initProject(manager string) error {
+	logger := utils.NewLogger()
+	executor := utils.NewExecutor()
+
+	projectDir, err := executor.GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %v", err)
+	}
+
+	logger.Info("Initializing Eel project in %s", projectDir)
+
+	var cfg *config.Config
+	hasConfig := executor.FileExists(filepath.Join(projectDir, config.FileName))
+	if hasConfig {
+		cfg, err = config.LoadConfig(projectDir)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+	} else {
+		cfg = &config.Config{
+			Paths: detectLayout(projectDir),
+			Dev: config.DevConfig{
+				Mode: "url",
+			},
+			Build: config.BuildConfig{
+				AppName:   filepath.Base(projectDir),
+				Icon:      "",
+				NoConsole: true,
+				OneFile:   true,
+			},
+		}
+	}
+
+	if !executor.FileExists(filepath.Join(projectDir, cfg.Paths.Entry)) {
+		return fmt.Errorf("entry file not found (looked for %s) - run eel init from the root of an Eel project", strings.Join(entryCandidates, ", "))
+	}
+	logger.Info("Entry file: %s", cfg.Paths.Entry)
+
+	webDir := filepath.Join(projectDir, cfg.Paths.WebDir)
+	hasWeb := executor.DirExists(webDir)
+	if hasWeb {
+		logger.Info("Web directory: %s", cfg.Paths.WebDir)
+	} else {
+		logger.Warning("web directory not found")
+	}
+
+	if manager == "" {
+		manager = cfg.Manager
+	}
+	if manager == "" {
+		d := pm.Detect(webDir)
+		manager = d.Manager
+		if d.Source == "PATH" {
+			logger.Info("Using package manager: %s", manager)
+		} else {
+			logger.Info("Detected package manager from %s: %s", d.Source, manager)
+		}
+	} else if d, ok := pm.DetectProject(webDir); ok && d.Manager != manager {
+		logger.Warning("Using %s, but %s/%s points to %s", manager, cfg.Paths.WebDir, d.Source, d.Manager)
+	}
+
+	if hasConfig {
+		logger.Info("eel.cli.json already exists, skipping")
+	} else {
+		if hasWeb && !executor.FileExists(filepath.Join(webDir, "package.json")) {
+			// Nothing builds a plain folder of static files: serve it as it is.
+			cfg.Paths.WebOutDir = cfg.Paths.WebDir
+		}
+		cfg.Manager = manager
+		if err := config.SaveConfig(projectDir, cfg); err != nil {
+			return fmt.Errorf("failed to save config: %v", err)
+		}
+		logger.Success("Created eel.cli.json")
+	}
+
+	if err := ensurePyproject(projectDir, manager); err != nil {
+		return err
+	}
+	if err := ensureGitignore(projectDir, cfg.Paths); err != nil {
+		return err
+	}
+
+	if hasWeb {
+		if !executor.FileExists(filepath.Join(webDir, "package.json")) {
+			logger.Info("No package.json in %s: eel dev and eel build serve it as it is", cfg.Paths.WebDir)
+		} else {
+			if err := ensureViteBuildConfig(projectDir, cfg.Paths); err != nil {
+				logger.Warning("Could not update vite config: %v", err)
+			}
+			if err := ensureEelScript(webDir); err != nil {
+				logger.Warning("Could not add eel.js to index.html: %v", err)
+			}
+		}
+	}
+
+	logger.Success("Project initialized!")
+	logger.Info("Next steps:")
+	logger.Info("  eel install")
+	logger.Info("  eel dev")
+
+	return nil
+}
+
+var entryCandidates = []string{"main.py", "app.py", "src/main.py", "src/app.py"}
+
+var webDirCandidates = []string{"web", "frontend", "client", "www", "src/web"}
+
+func detectLayout(projectDir string) config.PathsConfig {
+	executor := utils.NewExecutor()
+	paths := config.DefaultPaths()
+
+	for _, entry := range entryCandidates {
+		if executor.FileExists(filepath.Join(projectDir, filepath.FromSlash(entry))) {
+			paths.Entry = entry
+			break
+		}
+	}
+
+	for _, dir := range webDirCandidates {
+		webDir := filepath.Join(projectDir, filepath.FromSlash(dir))
+		if executor.FileExists(filepath.Join(webDir, "package.json")) || executor.FileExists(filepath.Join(webDir, "index.html")) {
+			paths.WebDir = dir
+			break
+		}
+	}
+
+	return paths
+}
+
+func ensurePyproject(projectDir, manager string) error {
+	logger := utils.NewLogger()
+	executor := utils.NewExecutor()
+
+	pyprojectPath := filepath.Join(projectDir, "pyproject.toml")
+	if executor.FileExists(pyprojectPath) {
+		logger.Info("pyproject.toml already exists, skipping")
+		return nil
+	}
+
+	data := template.NewData(filepath.Base(projectDir), manager)
+	data.Author, data.AuthorEmail = gitAuthor()
+
+	if version, err := os.ReadFile(filepath.Join(projectDir, ".python-version")); err == nil {
+		// pyenv allows several versions, one per line; the first one wins.
+		if line, _, _ := strings.Cut(strings.TrimSpace(string(version)), "\n"); line != "" {
+			if v, err := template.ParsePythonVersion(line); err != nil {
+				logger.Warning("Ignoring .python-version: %v", err)
+			} else {
+				data.PythonVersion = v
+			}
+		}
+	}
+
+	requirementsPath := filepath.Join(projectDir, "requirements.txt")
+	if executor.FileExists(requirementsPath) {
+		deps, err := parseRequirements(requirementsPath)
+		if err != nil {
+			return fmt.Errorf("failed to read requirements.txt: %v", err)
+		}
+		data.Dependencies = mergeEelDependency(deps)
+		logger.Info("Converting requirements.txt (%d dependencies)", len(deps))
+	}
+
+	content, err := template.RenderFile("pyproject.toml", data)
+	if err != nil {
+		return fmt.Errorf("failed to render pyproject.toml: %v", err)
+	}
+
+	if err := os.WriteFile(pyprojectPath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write pyproject.toml: %v", err)
+	}
+
+	logger.Success("Created pyproject.toml")
+
+	readmePath := filepath.Join(projectDir, "README.md")
+	if !executor.FileExists(readmePath) {
+		readme, err := template.RenderFile("README.md", data)
+		if err != nil {
+			return fmt.Errorf("failed to render README.md: %v", err)
+		}
+		if err := os.WriteFile(readmePath, readme, 0644); err != nil {
+			return fmt.Errorf("failed to write README.md: %v", err)
+		}
+		logger.Success("Created README.md")
+	}
+
+	return nil
+}
+
+// ensureGitignore adds the directories eel-cli writes to .gitignore, creating
+// it if needed. .eel/ matters most: .eel/generated holds the variables baked
+// into builds, which may be secrets. vendor/ is left out on purpose: eel
+// vendor records it in eel.cli.json for offline installs from a checkout.
+func ensureGitignore(projectDir string, paths config.PathsConfig) error {
+	logger := utils.NewLogger()
+
+	entries := []string{".eel/", "reports/", ".venv/", "__pycache__/", "node_modules/", "build/"}
+	for _, dir := range []string{paths.WebOutDir, paths.DistDir} {
+		if dir = strings.Trim(filepath.ToSlash(dir), "/"); dir != "" && dir != "." {
+			entries = append(entries, dir+"/")
+		}
+	}
+
+	gitignorePath := filepath.Join(projectDir, ".gitignore")
+	content, err := os.ReadFile(gitignorePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read .gitignore: %v", err)
+	}
+
+	present := map[string]bool{}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.Trim(strings.TrimSpace(line), "/")
+		present[line] = true
+	}
+
+	var missing []string
+	for _, entry := range entries {
+		if !present[strings.Trim(entry, "/")] {
+			missing = append(missing, entry)
+			present[strings.Trim(entry, "/")] = true
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		content = append(content, '\n')
+	}
+	content = append(content, strings.Join(missing, "\n")+"\n"...)
+	if err := os.WriteFile(gitignorePath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write .gitignore: %v", err)
+	}
+
+	logger.Success("Added %s to .gitignore", strings.Join(missing, ", "))
+	return nil
+}
+
+func parseRequirements(path string) ([]string, error) {
+	logger := utils.NewLogger()
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var deps []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if idx := strings.Index(line, " #"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "-") {
+			logger.Warning("Skipping unsupported requirements option: %s", line)
+			continue
+		}
+		if name, _ := splitRequirement(line); name == "" {
+			logger.Warning("Skipping requirement without a package name: %s", line)
+			continue
+		}
+		deps = append(deps, line)
+	}
+
+	return deps, scanner.Err()
+}
+
+func mergeEelDependency(deps []string) []string {
+	for _, dep := range deps {
+		if name, _ := splitRequirement(dep); normalizePythonName(name) == "eel" {
+			return deps
+		}
+	}
+
+	return append([]string{"Eel>=0.16.0"}, deps...)
+}
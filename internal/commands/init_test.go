@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRequirements(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requirements.txt")
+	content := `# app
+requests>=2.31  # http
+-r dev.txt
+==
+>=1.0
+
+uvicorn[standard]; python_version >= "3.11"
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parseRequirements(path)
+	if err != nil {
+		t.Fatalf("parseRequirements: %v", err)
+	}
+	want := []string{"requests>=2.31", `uvicorn[standard]; python_version >= "3.11"`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRequirements = %q, want %q", got, want)
+	}
+}
+
+func TestMergeEelDependency(t *testing.T) {
+	tests := []struct {
+		name string
+		deps []string
+		want []string
+	}{
+		{"adds eel", []string{"requests"}, []string{"Eel>=0.16.0", "requests"}},
+		{"empty", nil, []string{"Eel>=0.16.0"}},
+		{"keeps a pinned eel", []string{"eel==0.17.0", "requests"}, []string{"eel==0.17.0", "requests"}},
+		{"matches the normalized name", []string{"EEL [jinja2] >=0.16"}, []string{"EEL [jinja2] >=0.16"}},
+		{"does not match a prefix", []string{"eel-extras"}, []string{"Eel>=0.16.0", "eel-extras"}},
+		{"survives a line without a name", []string{"=="}, []string{"Eel>=0.16.0", "=="}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeEelDependency(tt.deps); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeEelDependency = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
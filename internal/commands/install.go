@@ -5,8 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
-	"eel-cli/internal/config"
+	"eel-cli/internal/pm"
 	"eel-cli/pkg/utils"
 
 	"github.com/urfave/cli/v3"
@@ -16,29 +17,49 @@ func InstallCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "install",
 		Usage: "Install project dependencies (web packages, uv, and create eel.d.ts)",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "frozen",
+				Usage: "Install exactly what the lockfiles say and fail if they are missing or out of date",
+			},
+			&cli.BoolFlag{
+				Name:  "offline",
+				Usage: "Install without network access, from the caches filled by eel vendor",
+			},
+			&cli.BoolFlag{
+				Name:  "sequential",
+				Usage: "Install Python and web dependencies one after another instead of concurrently",
+			},
+		},
 		Action: func(c context.Context, cmd *cli.Command) error {
-			return installDependencies()
+			opts := installOptions{
+				Frozen:  cmd.Bool("frozen"),
+				Offline: cmd.Bool("offline"),
+			}
+			return installDependencies(c, opts, cmd.Bool("sequential"))
 		},
 	}
 }
 
-func installDependencies() error {
+// installOptions selects how dependencies are installed.
+type installOptions struct {
+	Frozen  bool
+	Offline bool
+	// UVCache and WebCache are the vendored package locations used in
+	// offline mode; empty means the tools' own caches.
+	UVCache  string
+	WebCache string
+}
+
+func installDependencies(ctx context.Context, opts installOptions, sequential bool) error {
 	logger := utils.NewLogger()
 	executor := utils.NewExecutor()
 
-	projectDir, err := executor.GetWorkingDir()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %v", err)
-	}
-
-	cfg, err := config.LoadConfig(projectDir)
+	p, err := loadEelProject()
 	if err != nil {
-		return fmt.Errorf("failed to load config: %v", err)
-	}
-
-	if !executor.FileExists(filepath.Join(projectDir, "main.py")) {
-		return fmt.Errorf("not in an Eel project directory (main.py not found)")
+		return err
 	}
+	projectDir := p.Dir
 
 	logger.Info("Installing dependencies...")
 
@@ -53,29 +74,46 @@ func installDependencies() error {
 		logger.Info("uv is already installed")
 	}
 
-	logger.Info("Installing Python dependencies...")
-	ctx := context.Background()
-	if err := executor.RunCommand(ctx, projectDir, "uv", "sync"); err != nil {
-		return fmt.Errorf("failed to install Python dependencies: %v", err)
+	if opts.Offline {
+		if opts.UVCache, opts.WebCache, err = p.VendorDirs(); err != nil {
+			return err
+		}
 	}
-	logger.Success("Python dependencies installed")
 
-	webDir := filepath.Join(projectDir, "web")
-	if executor.DirExists(webDir) {
-		logger.Info("Installing web dependencies...")
+	tasks := []task{{
+		name:  "python",
+		title: "Installing Python dependencies",
+		run: func(ctx context.Context, executor *utils.Executor) error {
+			return syncPythonDependencies(ctx, executor, projectDir, opts)
+		},
+	}}
 
-		manager := cfg.Manager
-		if manager == "" {
-			manager = detectPackageManager()
+	webDir := p.WebDir()
+	if p.HasWebBuild() {
+		manager, err := p.PackageManager()
+		if err != nil {
+			return err
 		}
+		tasks = append(tasks, task{
+			name:  "web",
+			title: "Installing web dependencies",
+			run: func(ctx context.Context, executor *utils.Executor) error {
+				return installWebDependencies(ctx, executor, webDir, manager, opts)
+			},
+		})
+	}
 
-		if err := installWebDependencies(webDir, manager); err != nil {
-			return fmt.Errorf("failed to install web dependencies: %v", err)
-		}
-		logger.Success("Web dependencies installed")
+	var results []taskResult
+	if sequential {
+		results = runTasksSequential(ctx, tasks)
+	} else {
+		results = runTasksParallel(ctx, tasks, true)
+	}
+	if err := printTaskSummary(logger, results); err != nil {
+		return fmt.Errorf("failed to install dependencies: %v", err)
 	}
 
-	if err := createEelTypes(); err != nil {
+	if err := createEelTypes(webDir); err != nil {
 		logger.Warning("Failed to create eel.d.ts: %v", err)
 	} else {
 		logger.Success("Created eel.d.ts")
@@ -98,45 +136,64 @@ func installUV() error {
 	return nil
 }
 
-func detectPackageManager() string {
-	executor := utils.NewExecutor()
+// syncPythonDependencies runs uv sync. In frozen mode uv.lock must exist and
+// match pyproject.toml; uv sync --frozen alone would not notice a stale lock.
+// In offline mode uv only looks at its cache, the vendored one if there is.
+func syncPythonDependencies(ctx context.Context, executor *utils.Executor, projectDir string, opts installOptions, extraArgs ...string) error {
+	var offline []string
+	if opts.Offline {
+		offline = append(offline, "--offline")
+		if opts.UVCache != "" {
+			offline = append(offline, "--cache-dir", opts.UVCache)
+		}
+	}
 
-	managers := []string{"bun", "pnpm", "yarn", "npm"}
-	for _, manager := range managers {
-		if executor.CommandExists(manager) {
-			return manager
+	args := []string{"sync"}
+	if opts.Frozen {
+		if !executor.FileExists(filepath.Join(projectDir, "uv.lock")) {
+			return fmt.Errorf("uv.lock not found - run eel install without --frozen and commit it")
 		}
+		if err := executor.RunCommand(ctx, projectDir, "uv", append([]string{"lock", "--check"}, offline...)...); err != nil {
+			return fmt.Errorf("uv.lock is out of date with pyproject.toml: %v", err)
+		}
+		args = append(args, "--frozen")
 	}
 
-	return "npm"
+	args = append(append(args, offline...), extraArgs...)
+	return executor.RunCommand(ctx, projectDir, "uv", args...)
 }
 
-func installWebDependencies(webDir, manager string) error {
-	executor := utils.NewExecutor()
-	ctx := context.Background()
+// installWebDependencies runs the manager's install. In frozen mode it
+// requires the manager's lockfile and lets the manager reject a stale one.
+func installWebDependencies(ctx context.Context, executor *utils.Executor, webDir string, manager pm.PackageManager, opts installOptions) error {
+	if opts.Frozen && !hasLockfile(webDir, manager) {
+		return fmt.Errorf("no %s lockfile found (expected %s) - run eel install without --frozen and commit it", manager.Name(), strings.Join(manager.Lockfiles(), " or "))
+	}
 
-	switch manager {
-	case "npm":
-		return executor.RunCommand(ctx, webDir, "npm", "install")
-	case "yarn":
-		return executor.RunCommand(ctx, webDir, "yarn", "install")
-	case "pnpm":
-		return executor.RunCommand(ctx, webDir, "pnpm", "install")
-	case "bun":
-		return executor.RunCommand(ctx, webDir, "bun", "install")
-	default:
-		return fmt.Errorf("unsupported package manager: %s", manager)
+	args := manager.Install()
+	var env []string
+	switch {
+	case opts.Offline:
+		args = manager.OfflineInstall(opts.WebCache, opts.Frozen)
+		env = append(os.Environ(), manager.CacheEnv(opts.WebCache)...)
+	case opts.Frozen:
+		args = manager.FrozenInstall()
 	}
+	return executor.RunCommandEnv(ctx, webDir, env, manager.Name(), args...)
 }
 
-func createEelTypes() error {
-	executor := utils.NewExecutor()
-	projectDir, err := executor.GetWorkingDir()
-	if err != nil {
-		return err
+func hasLockfile(webDir string, manager pm.PackageManager) bool {
+	for _, lockfile := range manager.Lockfiles() {
+		if utils.NewExecutor().FileExists(filepath.Join(webDir, lockfile)) {
+			return true
+		}
 	}
+	return false
+}
+
+func createEelTypes(webDir string) error {
+	executor := utils.NewExecutor()
 
-	webDir := filepath.Join(projectDir, "web")
 	if !executor.DirExists(webDir) {
 		return fmt.Errorf("web directory not found")
 	}
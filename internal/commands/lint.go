@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"eel-cli/pkg/utils"
+
+	"github.com/urfave/cli/v3"
+)
+
+func LintCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "lint",
+		Usage: "Run ruff, mypy and the web lint script",
+		Action: func(c context.Context, cmd *cli.Command) error {
+			return lintProject(c)
+		},
+	}
+}
+
+func FmtCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "fmt",
+		Usage: "Format Python code with ruff and web code with the web format script",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "check",
+				Usage: "Only check the formatting and fail if files would change",
+			},
+		},
+		Action: func(c context.Context, cmd *cli.Command) error {
+			return formatProject(c, cmd.Bool("check"))
+		},
+	}
+}
+
+func lintProject(ctx context.Context) error {
+	logger := utils.NewLogger()
+
+	p, err := loadProject()
+	if err != nil {
+		return err
+	}
+
+	hasPython, err := checkPythonTools(p)
+	if err != nil {
+		return err
+	}
+
+	var tasks []task
+	if hasPython {
+		tasks = append(tasks,
+			uvRunTask("ruff", p.Dir, "ruff", "check"),
+			uvRunTask("mypy", p.Dir, "mypy", filepath.FromSlash(p.Config.Paths.Entry)),
+		)
+	}
+
+	t, ok, err := webScriptTask(p, "lint")
+	if err != nil {
+		return err
+	} else if ok {
+		tasks = append(tasks, t)
+	} else {
+		logger.Info("No lint script in package.json, skipping web")
+	}
+
+	return runChecks(ctx, tasks, "lint")
+}
+
+// formatProject runs ruff format and the web format script. In check mode
+// the web side runs a "format:check" script, or "format" with --check
+// (the prettier convention) when there is none.
+func formatProject(ctx context.Context, check bool) error {
+	logger := utils.NewLogger()
+
+	p, err := loadProject()
+	if err != nil {
+		return err
+	}
+
+	hasPython, err := checkPythonTools(p)
+	if err != nil {
+		return err
+	}
+
+	var tasks []task
+	if hasPython {
+		args := []string{"ruff", "format"}
+		if check {
+			args = append(args, "--check")
+		}
+		tasks = append(tasks, uvRunTask("ruff", p.Dir, args...))
+	}
+
+	var t task
+	var ok bool
+	if check {
+		if t, ok, err = webScriptTask(p, "format:check"); err == nil && !ok {
+			t, ok, err = webScriptTask(p, "format", "--check")
+		}
+	} else {
+		t, ok, err = webScriptTask(p, "format")
+	}
+	if err != nil {
+		return err
+	} else if ok {
+		tasks = append(tasks, t)
+	} else {
+		logger.Info("No format script in package.json, skipping web")
+	}
+
+	return runChecks(ctx, tasks, "format")
+}
+
+// runChecks runs independent tools side by side. Unlike installs, one
+// failure does not cancel the others: every problem should be reported.
+func runChecks(ctx context.Context, tasks []task, what string) error {
+	if len(tasks) == 0 {
+		return fmt.Errorf("nothing to %s: no pyproject.toml and no matching script in package.json", what)
+	}
+
+	return printTaskSummary(utils.NewLogger(), runTasksParallel(ctx, tasks, false))
+}
+
+// checkPythonTools reports whether the project has Python tooling to run,
+// requiring uv when it does.
+func checkPythonTools(p *project) (bool, error) {
+	executor := utils.NewExecutor()
+
+	if !executor.FileExists(filepath.Join(p.Dir, "pyproject.toml")) {
+		utils.NewLogger().Info("No pyproject.toml, skipping Python")
+		return false, nil
+	}
+	if !executor.CommandExists("uv") {
+		return false, fmt.Errorf("uv is not installed. Please install it first")
+	}
+	return true, nil
+}
+
+// uvRunTask runs a tool from the project's environment with uv run.
+func uvRunTask(name, projectDir string, args ...string) task {
+	return task{
+		name:  name,
+		title: "Running " + name,
+		run: func(ctx context.Context, executor *utils.Executor) error {
+			return executor.RunCommand(ctx, projectDir, "uv", append([]string{"run"}, args...)...)
+		},
+	}
+}
+
+// webScriptTask runs a package.json script through the project's package
+// manager. It returns false when there is no web project or no such script.
+func webScriptTask(p *project, script string, args ...string) (task, bool, error) {
+	webDir := p.WebDir()
+	pkg, err := readPackageJSON(filepath.Join(webDir, "package.json"))
+	if os.IsNotExist(err) {
+		return task{}, false, nil
+	} else if err != nil {
+		return task{}, false, err
+	}
+	if pkg.Scripts[script] == "" {
+		return task{}, false, nil
+	}
+
+	manager, err := p.PackageManager()
+	if err != nil {
+		return task{}, false, err
+	}
+
+	return task{
+		name:  "web",
+		title: "Running the web " + script + " script",
+		run: func(ctx context.Context, executor *utils.Executor) error {
+			return executor.RunCommand(ctx, webDir, manager.Name(), manager.Run(script, args...)...)
+		},
+	}, true, nil
+}
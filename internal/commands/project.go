@@ -0,0 +1,212 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"eel-cli/internal/config"
+	"eel-cli/internal/pm"
+	"eel-cli/pkg/utils"
+)
+
+type project struct {
+	Dir    string
+	Config *config.Config
+
+	manager string
+}
+
+func loadProject() (*project, error) {
+	executor := utils.NewExecutor()
+
+	workDir, err := executor.GetWorkingDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %v", err)
+	}
+
+	projectDir := workDir
+	if root, ok := config.FindProjectRoot(workDir); ok {
+		projectDir = root
+	}
+
+	cfg, err := config.LoadConfig(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %v", err)
+	}
+
+	return &project{
+		Dir:    projectDir,
+		Config: cfg,
+	}, nil
+}
+
+// loadEelProject is loadProject for commands that need the Python entry file.
+func loadEelProject() (*project, error) {
+	p, err := loadProject()
+	if err != nil {
+		return nil, err
+	}
+
+	if !utils.NewExecutor().FileExists(p.EntryPath()) {
+		return nil, fmt.Errorf("not in an Eel project directory (%s not found)", p.Config.Paths.Entry)
+	}
+
+	return p, nil
+}
+
+func (p *project) path(rel string) string {
+	if filepath.IsAbs(rel) {
+		return rel
+	}
+	return filepath.Join(p.Dir, rel)
+}
+
+func (p *project) EntryPath() string {
+	return p.path(p.Config.Paths.Entry)
+}
+
+func (p *project) WebDir() string {
+	return p.path(p.Config.Paths.WebDir)
+}
+
+func (p *project) WebOutDir() string {
+	return p.path(p.Config.Paths.WebOutDir)
+}
+
+func (p *project) DistDir() string {
+	return p.path(p.Config.Paths.DistDir)
+}
+
+// Manager returns the configured package manager, or the one detected from
+// the web directory. A configured manager that disagrees with package.json
+// or the lockfiles is used anyway, with a warning.
+func (p *project) Manager() string {
+	if p.manager != "" {
+		return p.manager
+	}
+
+	if p.Config.Manager == "" {
+		p.manager = pm.Detect(p.WebDir()).Manager
+		return p.manager
+	}
+
+	p.manager = p.Config.Manager
+	if d, ok := pm.DetectProject(p.WebDir()); ok && d.Manager != p.manager {
+		utils.NewLogger().Warning("%s sets manager %q, but %s/%s points to %s", config.FileName, p.manager, p.Config.Paths.WebDir, d.Source, d.Manager)
+	}
+	return p.manager
+}
+
+// PackageManager returns the web package manager for the project.
+func (p *project) PackageManager() (pm.PackageManager, error) {
+	return pm.New(p.Manager(), p.WebDir())
+}
+
+// VendorDirs returns the absolute uv cache and web cache recorded by eel
+// vendor. Unset locations are empty; recorded ones must exist.
+func (p *project) VendorDirs() (string, string, error) {
+	uvCache, err := p.vendorDir(p.Config.Vendor.UVCache)
+	if err != nil {
+		return "", "", err
+	}
+	webCache, err := p.vendorDir(p.Config.Vendor.WebCache)
+	if err != nil {
+		return "", "", err
+	}
+	return uvCache, webCache, nil
+}
+
+func (p *project) vendorDir(rel string) (string, error) {
+	if rel == "" {
+		return "", nil
+	}
+	dir := p.path(rel)
+	if !utils.NewExecutor().DirExists(dir) {
+		return "", fmt.Errorf("vendor directory %s not found - run eel vendor first", rel)
+	}
+	return dir, nil
+}
+
+func (p *project) RequireWebDir() (string, error) {
+	webDir := p.WebDir()
+	if !utils.NewExecutor().DirExists(webDir) {
+		return "", fmt.Errorf("web directory not found (%s)", p.Config.Paths.WebDir)
+	}
+	return webDir, nil
+}
+
+// HasWebBuild reports whether the web directory is a JavaScript project with
+// a package.json. Plain folders of static files are served as they are.
+func (p *project) HasWebBuild() bool {
+	return utils.NewExecutor().FileExists(filepath.Join(p.WebDir(), "package.json"))
+}
+
+// Env returns the environment for child processes: the current environment
+// plus .env and .env.<mode> from the project root.
+func (p *project) Env(mode string) ([]string, error) {
+	vars, err := p.DotenvVars(mode)
+	if err != nil {
+		return nil, err
+	}
+	return utils.MergeEnv(os.Environ(), vars), nil
+}
+
+// ShellEnv is Env with the project's .venv activated and the web
+// directory's node_modules/.bin on PATH, as if both were set up in a shell.
+func (p *project) ShellEnv(mode string) ([]string, error) {
+	env, err := p.Env(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	venv := p.path(".venv")
+	venvBin := filepath.Join(venv, "bin")
+	if runtime.GOOS == "windows" {
+		venvBin = filepath.Join(venv, "Scripts")
+	}
+	webBin := filepath.Join(p.WebDir(), "node_modules", ".bin")
+
+	path := strings.Join([]string{venvBin, webBin, lookupEnv(env, "PATH")}, string(os.PathListSeparator))
+	env = setEnv(env, "PATH", path)
+	env = setEnv(env, "VIRTUAL_ENV", venv)
+	return env, nil
+}
+
+// lookupEnv and setEnv work on os.Environ style lists. Names are matched
+// case-insensitively on Windows, where PATH is usually spelled Path.
+func lookupEnv(env []string, key string) string {
+	for _, kv := range env {
+		if k, v, _ := strings.Cut(kv, "="); envKeyEqual(k, key) {
+			return v
+		}
+	}
+	return ""
+}
+
+func setEnv(env []string, key, value string) []string {
+	out := make([]string, 0, len(env)+1)
+	for _, kv := range env {
+		if k, _, _ := strings.Cut(kv, "="); !envKeyEqual(k, key) {
+			out = append(out, kv)
+		}
+	}
+	return append(out, key+"="+value)
+}
+
+func envKeyEqual(a, b string) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+func (p *project) DotenvVars(mode string) (map[string]string, error) {
+	vars, err := utils.LoadDotenvFiles(p.Dir, ".env", ".env."+mode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load .env files: %v", err)
+	}
+	return vars, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"eel-cli/pkg/utils"
 
@@ -16,108 +17,194 @@ func PyCommand() *cli.Command {
 		Usage: "Manage Python packages",
 		Commands: []*cli.Command{
 			{
-				Name:  "add",
-				Usage: "Add a Python package",
-				Flags: []cli.Flag{
+				Name:      "add",
+				Usage:     "Add Python packages",
+				ArgsUsage: "<package[extras][specifier]>...",
+				Flags: append(pyTargetFlags(),
 					&cli.BoolFlag{
-						Name:    "dev",
-						Usage:   "Add as dev dependency",
-						Aliases: []string{"d"},
+						Name:    "exact",
+						Usage:   "Pin the resolved version with ==",
+						Aliases: []string{"E"},
 					},
-				},
+				),
 				Action: func(c context.Context, cmd *cli.Command) error {
-					args := cmd.Args().Slice()
-					if len(args) == 0 {
+					packages := cmd.Args().Slice()
+					if len(packages) == 0 {
 						return fmt.Errorf("package name is required")
 					}
+					if err := validateSpecs(packages, validatePythonSpec, true); err != nil {
+						return err
+					}
 
-					packageName := args[0]
-					isDev := cmd.Bool("dev")
+					target, err := pyTargetArgs(cmd)
+					if err != nil {
+						return err
+					}
 
-					return addPythonPackage(packageName, isDev)
+					return addPythonPackages(packages, target, cmd.Bool("exact"))
 				},
 			},
 			{
-				Name:  "remove",
-				Usage: "Remove a Python package",
+				Name:      "remove",
+				Usage:     "Remove Python packages",
+				ArgsUsage: "<package>...",
+				Flags:     pyTargetFlags(),
 				Action: func(c context.Context, cmd *cli.Command) error {
-					args := cmd.Args().Slice()
-					if len(args) == 0 {
+					packages := cmd.Args().Slice()
+					if len(packages) == 0 {
 						return fmt.Errorf("package name is required")
 					}
+					if err := validateSpecs(packages, validatePythonSpec, false); err != nil {
+						return err
+					}
+
+					target, err := pyTargetArgs(cmd)
+					if err != nil {
+						return err
+					}
 
-					packageName := args[0]
-					return removePythonPackage(packageName)
+					return removePythonPackages(packages, target)
+				},
+			},
+			{
+				Name:  "list",
+				Usage: "List direct Python dependencies",
+				Flags: []cli.Flag{jsonFlag()},
+				Action: func(c context.Context, cmd *cli.Command) error {
+					return listPythonPackages(false, cmd.Bool("json"))
+				},
+			},
+			{
+				Name:  "outdated",
+				Usage: "List Python dependencies with newer versions",
+				Flags: []cli.Flag{jsonFlag()},
+				Action: func(c context.Context, cmd *cli.Command) error {
+					return listPythonPackages(true, cmd.Bool("json"))
+				},
+			},
+			{
+				Name:      "upgrade",
+				Usage:     "Upgrade Python packages within their version specifiers",
+				ArgsUsage: "[package]...",
+				Action: func(c context.Context, cmd *cli.Command) error {
+					packages := cmd.Args().Slice()
+					if err := validateSpecs(packages, validatePythonSpec, false); err != nil {
+						return err
+					}
+					return upgradePythonPackages(packages)
 				},
 			},
 		},
 	}
 }
 
-func addPythonPackage(packageName string, isDev bool) error {
-	logger := utils.NewLogger()
-	executor := utils.NewExecutor()
-
-	projectDir, err := executor.GetWorkingDir()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %v", err)
+// pyTargetFlags select where in pyproject.toml a dependency goes.
+func pyTargetFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "dev",
+			Usage:   "Use the dev dependency group",
+			Aliases: []string{"d"},
+		},
+		&cli.StringFlag{
+			Name:  "group",
+			Usage: "Use the given dependency group",
+		},
+		&cli.StringFlag{
+			Name:  "optional",
+			Usage: "Use the given optional dependency (extra)",
+		},
 	}
+}
 
-	if !executor.FileExists(filepath.Join(projectDir, "pyproject.toml")) {
-		return fmt.Errorf("pyproject.toml not found - not a Python project")
+func pyTargetArgs(cmd *cli.Command) ([]string, error) {
+	var args []string
+	if cmd.Bool("dev") {
+		args = append(args, "--group", "dev")
+	}
+	if group := cmd.String("group"); group != "" {
+		if !pyNamePattern.MatchString(group) {
+			return nil, fmt.Errorf("invalid group name: %s", group)
+		}
+		args = append(args, "--group", group)
+	}
+	if extra := cmd.String("optional"); extra != "" {
+		if !pyNamePattern.MatchString(extra) {
+			return nil, fmt.Errorf("invalid extra name: %s", extra)
+		}
+		args = append(args, "--optional", extra)
 	}
 
-	if !executor.CommandExists("uv") {
-		return fmt.Errorf("uv is not installed. Please install it first")
+	if len(args) > 2 {
+		return nil, fmt.Errorf("--dev, --group and --optional are mutually exclusive")
 	}
 
-	logger.Info("Adding Python package: %s (dev: %v)", packageName, isDev)
+	return args, nil
+}
 
-	ctx := context.Background()
-	var args []string
+func addPythonPackages(packages, target []string, exact bool) error {
+	logger := utils.NewLogger()
+	executor := utils.NewExecutor()
 
-	if isDev {
-		args = []string{"add", "--group", "dev", packageName}
-	} else {
-		args = []string{"add", packageName}
+	p, err := loadPythonProject()
+	if err != nil {
+		return err
 	}
+	projectDir := p.Dir
+
+	logger.Info("Adding Python packages: %s", strings.Join(packages, ", "))
+
+	ctx := context.Background()
+	args := append(append([]string{"add"}, target...), packages...)
 
 	err = executor.RunCommand(ctx, projectDir, "uv", args...)
 	if err != nil {
-		return fmt.Errorf("failed to add Python package: %v", err)
+		return fmt.Errorf("failed to add Python packages: %v", err)
 	}
 
-	logger.Success("Python package %s added successfully", packageName)
+	if exact {
+		// uv only pins with --bounds exact behind --preview, so pin the
+		// versions uv just locked with a second add.
+		locked, err := lockedVersions(filepath.Join(projectDir, "uv.lock"))
+		if err != nil {
+			return fmt.Errorf("failed to read uv.lock: %v", err)
+		}
+		pins, err := exactPins(packages, locked)
+		if err != nil {
+			return err
+		}
+
+		logger.Info("Pinning %s", strings.Join(pins, ", "))
+		args = append(append([]string{"add"}, target...), pins...)
+		if err := executor.RunCommand(ctx, projectDir, "uv", args...); err != nil {
+			return fmt.Errorf("failed to pin Python packages: %v", err)
+		}
+	}
+
+	logger.Success("Added %s", strings.Join(packages, ", "))
 	return nil
 }
 
-func removePythonPackage(packageName string) error {
+func removePythonPackages(packages, target []string) error {
 	logger := utils.NewLogger()
 	executor := utils.NewExecutor()
 
-	projectDir, err := executor.GetWorkingDir()
+	p, err := loadPythonProject()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %v", err)
-	}
-
-	if !executor.FileExists(filepath.Join(projectDir, "pyproject.toml")) {
-		return fmt.Errorf("pyproject.toml not found - not a Python project")
-	}
-
-	if !executor.CommandExists("uv") {
-		return fmt.Errorf("uv is not installed. Please install it first")
+		return err
 	}
+	projectDir := p.Dir
 
-	logger.Info("Removing Python package: %s", packageName)
+	logger.Info("Removing Python packages: %s", strings.Join(packages, ", "))
 
 	ctx := context.Background()
-	args := []string{"remove", packageName}
+	args := append(append([]string{"remove"}, target...), packages...)
 
 	err = executor.RunCommand(ctx, projectDir, "uv", args...)
 	if err != nil {
-		return fmt.Errorf("failed to remove Python package: %v", err)
+		return fmt.Errorf("failed to remove Python packages: %v", err)
 	}
 
-	logger.Success("Python package %s removed successfully", packageName)
+	logger.Success("Removed %s", strings.Join(packages, ", "))
 	return nil
 }
@@ -0,0 +1,310 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"eel-cli/pkg/utils"
+)
+
+// uvTreeLine matches a direct dependency in `uv tree --depth 1`, e.g.
+// "├── pytest v8.3.4 (group: dev) (latest: v8.3.5)".
+var uvTreeLine = regexp.MustCompile(`^[├└]── (\S+) v(\S+)(.*)$`)
+
+var uvTreeAnnotation = regexp.MustCompile(`\((group|extra|latest): v?([^)]+)\)`)
+
+func loadPythonProject() (*project, error) {
+	executor := utils.NewExecutor()
+
+	p, err := loadProject()
+	if err != nil {
+		return nil, err
+	}
+
+	if !executor.FileExists(filepath.Join(p.Dir, "pyproject.toml")) {
+		return nil, fmt.Errorf("pyproject.toml not found - not a Python project")
+	}
+
+	if !executor.CommandExists("uv") {
+		return nil, fmt.Errorf("uv is not installed. Please install it first")
+	}
+
+	return p, nil
+}
+
+func listPythonPackages(outdated, asJSON bool) error {
+	p, err := loadPythonProject()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"tree", "--depth", "1"}
+	if outdated {
+		args = append(args, "--outdated")
+	}
+
+	out, err := utils.NewExecutor().RunCommandOutput(context.Background(), p.Dir, "uv", args...)
+	if err != nil {
+		return fmt.Errorf("failed to read Python dependencies: %v", err)
+	}
+
+	specifiers, err := pyprojectSpecifiers(filepath.Join(p.Dir, "pyproject.toml"))
+	if err != nil {
+		return err
+	}
+
+	deps := parseUVTree(out, specifiers)
+	if outdated {
+		var stale []dependency
+		for _, d := range deps {
+			if d.Latest != "" {
+				stale = append(stale, d)
+			}
+		}
+		return printDependencies(stale, asJSON, "All Python packages are up to date")
+	}
+
+	return printDependencies(deps, asJSON, "No Python dependencies")
+}
+
+func parseUVTree(out string, specifiers map[string]string) []dependency {
+	var deps []dependency
+
+	for _, line := range strings.Split(out, "\n") {
+		m := uvTreeLine.FindStringSubmatch(strings.TrimRight(line, " \r"))
+		if m == nil {
+			continue
+		}
+
+		d := dependency{Name: m[1], Current: m[2], Wanted: specifiers[normalizePythonName(m[1])], Type: "prod"}
+		for _, a := range uvTreeAnnotation.FindAllStringSubmatch(m[3], -1) {
+			switch a[1] {
+			case "group":
+				// Dependency groups are not installed with the app.
+				d.Type = "dev"
+			case "latest":
+				d.Latest = a[2]
+			}
+		}
+		deps = append(deps, d)
+	}
+
+	return deps
+}
+
+// pyprojectSpecifiers collects the version specifiers declared in
+// pyproject.toml (project dependencies, optional dependencies and dependency
+// groups), keyed by normalized package name.
+func pyprojectSpecifiers(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	specifiers := map[string]string{}
+	section := ""
+	inArray := false
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if !inArray && strings.HasPrefix(line, "[") && !strings.Contains(line, "=") {
+			section = strings.Trim(line, "[] ")
+			continue
+		}
+
+		if !inArray {
+			key, value, ok := strings.Cut(line, "=")
+			if !ok || !strings.HasPrefix(strings.TrimSpace(value), "[") {
+				continue
+			}
+			key = strings.TrimSpace(key)
+			isDeps := (section == "project" && key == "dependencies") ||
+				section == "project.optional-dependencies" ||
+				section == "dependency-groups"
+			if !isDeps {
+				continue
+			}
+			line = strings.TrimSpace(value)[1:]
+			inArray = true
+		}
+
+		reqs, rest := quotedStrings(line)
+		for _, req := range reqs {
+			name, spec := splitRequirement(req)
+			if name != "" {
+				specifiers[normalizePythonName(name)] = spec
+			}
+		}
+		if strings.Contains(rest, "]") {
+			inArray = false
+		}
+	}
+
+	return specifiers, scanner.Err()
+}
+
+// quotedStrings returns the string literals in line and what is left of
+// the line without them.
+func quotedStrings(line string) ([]string, string) {
+	var out []string
+	var rest strings.Builder
+	for {
+		start := strings.IndexAny(line, `"'`)
+		if start < 0 {
+			rest.WriteString(line)
+			return out, rest.String()
+		}
+		end := strings.IndexByte(line[start+1:], line[start])
+		if end < 0 {
+			rest.WriteString(line)
+			return out, rest.String()
+		}
+		rest.WriteString(line[:start])
+		out = append(out, line[start+1:start+1+end])
+		line = line[start+end+2:]
+	}
+}
+
+// splitRequirement splits "uvicorn[standard]>=0.30; python_version<'3.13'"
+// into the name and the version specifier.
+func splitRequirement(req string) (string, string) {
+	req, _, _ = strings.Cut(req, ";")
+	idx := strings.IndexAny(req, "[~=!<>@ ")
+	if idx < 0 {
+		return strings.TrimSpace(req), ""
+	}
+
+	name, rest := req[:idx], req[idx:]
+	if strings.HasPrefix(rest, "[") {
+		if end := strings.IndexByte(rest, ']'); end >= 0 {
+			rest = rest[end+1:]
+		}
+	}
+	return strings.TrimSpace(name), strings.TrimSpace(rest)
+}
+
+// lockedVersions reads the version of each package in uv.lock, keyed by
+// normalized package name. Packages locked at several versions (forked
+// resolutions) map to "".
+func lockedVersions(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	versions := map[string]string{}
+	name := ""
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			name = ""
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch strings.TrimSpace(key) {
+		case "name":
+			name = normalizePythonName(value)
+		case "version":
+			if name == "" {
+				continue
+			}
+			if v, seen := versions[name]; seen && v != value {
+				value = ""
+			}
+			versions[name] = value
+		}
+	}
+
+	return versions, scanner.Err()
+}
+
+// exactPins turns "uvicorn[standard]>=0.30; python_version<'3.13'" into
+// "uvicorn[standard]==0.32.1; python_version<'3.13'" using the locked
+// versions.
+func exactPins(packages []string, locked map[string]string) ([]string, error) {
+	pins := make([]string, len(packages))
+	for i, req := range packages {
+		name, spec := splitRequirement(req)
+		if strings.HasPrefix(spec, "@") {
+			return nil, fmt.Errorf("cannot pin %s: it is a direct reference", name)
+		}
+		version, ok := locked[normalizePythonName(name)]
+		if !ok {
+			return nil, fmt.Errorf("%s is not in uv.lock", name)
+		}
+		if version == "" {
+			return nil, fmt.Errorf("%s is locked at several versions, pin it by hand", name)
+		}
+
+		pin := name
+		if rest := strings.TrimSpace(req[len(name):]); strings.HasPrefix(rest, "[") {
+			if end := strings.IndexByte(rest, ']'); end >= 0 {
+				pin += rest[:end+1]
+			}
+		}
+		pin += "==" + version
+		if _, marker, ok := strings.Cut(req, ";"); ok {
+			pin += "; " + strings.TrimSpace(marker)
+		}
+		pins[i] = pin
+	}
+	return pins, nil
+}
+
+// normalizePythonName applies PEP 503 name normalization.
+func normalizePythonName(name string) string {
+	return strings.ToLower(pyNameSeparators.ReplaceAllString(name, "-"))
+}
+
+var pyNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+func upgradePythonPackages(packages []string) error {
+	logger := utils.NewLogger()
+	executor := utils.NewExecutor()
+
+	p, err := loadPythonProject()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"lock"}
+	if len(packages) == 0 {
+		logger.Info("Upgrading Python packages")
+		args = append(args, "--upgrade")
+	} else {
+		logger.Info("Upgrading Python packages: %s", strings.Join(packages, ", "))
+		for _, name := range packages {
+			args = append(args, "--upgrade-package", name)
+		}
+	}
+
+	ctx := context.Background()
+	if err := executor.RunCommand(ctx, p.Dir, "uv", args...); err != nil {
+		return fmt.Errorf("failed to upgrade Python packages: %v", err)
+	}
+	if err := executor.RunCommand(ctx, p.Dir, "uv", "sync"); err != nil {
+		return fmt.Errorf("failed to sync Python packages: %v", err)
+	}
+
+	logger.Success("Python packages upgraded")
+	return nil
+}
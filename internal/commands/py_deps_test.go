@@ -0,0 +1,212 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseUVTree(t *testing.T) {
+	specifiers := map[string]string{"eel": ">=0.16.0", "pytest": ">=8", "typer": ">=0.12"}
+
+	tests := []struct {
+		name string
+		out  string
+		want []dependency
+	}{
+		{
+			name: "root line and plain dependencies",
+			out: "my-app v0.1.0\n" +
+				"├── eel v0.17.0\n" +
+				"└── requests v2.32.3\n",
+			want: []dependency{
+				{Name: "eel", Current: "0.17.0", Wanted: ">=0.16.0", Type: "prod"},
+				{Name: "requests", Current: "2.32.3", Type: "prod"},
+			},
+		},
+		{
+			name: "group, extra and latest annotations",
+			out: "my-app v0.1.0\n" +
+				"├── eel v0.17.0 (latest: v0.18.1)\n" +
+				"├── typer v0.12.5 (extra: cli)\n" +
+				"└── pytest v8.3.4 (group: dev) (latest: v8.3.5)\n",
+			want: []dependency{
+				{Name: "eel", Current: "0.17.0", Wanted: ">=0.16.0", Latest: "0.18.1", Type: "prod"},
+				{Name: "typer", Current: "0.12.5", Wanted: ">=0.12", Type: "prod"},
+				{Name: "pytest", Current: "8.3.4", Wanted: ">=8", Latest: "8.3.5", Type: "dev"},
+			},
+		},
+		{
+			name: "names are normalized for the specifier lookup",
+			out:  "my-app v0.1.0\r\n└── Eel v0.17.0 \r\n",
+			want: []dependency{
+				{Name: "Eel", Current: "0.17.0", Wanted: ">=0.16.0", Type: "prod"},
+			},
+		},
+		{
+			name: "transitive and warning lines are skipped",
+			out: "warning: `VIRTUAL_ENV` does not match the project environment\n" +
+				"my-app v0.1.0\n" +
+				"├── eel v0.17.0\n" +
+				"│   └── bottle v0.13.2\n" +
+				"└── pytest v8.3.4 (group: dev)\n",
+			want: []dependency{
+				{Name: "eel", Current: "0.17.0", Wanted: ">=0.16.0", Type: "prod"},
+				{Name: "pytest", Current: "8.3.4", Wanted: ">=8", Type: "dev"},
+			},
+		},
+		{
+			name: "no dependencies",
+			out:  "my-app v0.1.0\n",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseUVTree(tt.out, specifiers)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseUVTree =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPyprojectSpecifiers(t *testing.T) {
+	tests := []struct {
+		name      string
+		pyproject string
+		want      map[string]string
+	}{
+		{
+			name: "inline arrays",
+			pyproject: `[project]
+name = "my-app"
+dependencies = ["Eel>=0.16.0", "requests"]
+`,
+			want: map[string]string{"eel": ">=0.16.0", "requests": ""},
+		},
+		{
+			name: "multiline arrays with comments",
+			pyproject: `[project]
+name = "my-app"
+dependencies = [
+    # the GUI
+    "eel>=0.16.0",
+    'uvicorn[standard]>=0.30; python_version < "3.13"',  # quoted marker
+]
+`,
+			want: map[string]string{"eel": ">=0.16.0", "uvicorn": ">=0.30"},
+		},
+		{
+			name: "optional dependencies and dependency groups",
+			pyproject: `[project]
+dependencies = ["eel"]
+
+[project.optional-dependencies]
+cli = ["typer>=0.12"]
+
+[dependency-groups]
+dev = [
+    "pytest>=8",
+    "Ruff_Lint.Plugin ~= 1.2",
+]
+`,
+			want: map[string]string{"eel": "", "typer": ">=0.12", "pytest": ">=8", "ruff-lint-plugin": "~= 1.2"},
+		},
+		{
+			name: "arrays outside the dependency tables are ignored",
+			pyproject: `[project]
+classifiers = ["Programming Language :: Python"]
+dependencies = ["eel==0.17.0"]
+
+[tool.ruff]
+extend-select = ["I", "UP"]
+
+[tool.uv]
+dev-dependencies = ["mypy"]
+`,
+			want: map[string]string{"eel": "==0.17.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pyproject.toml")
+			if err := os.WriteFile(path, []byte(tt.pyproject), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := pyprojectSpecifiers(path)
+			if err != nil {
+				t.Fatalf("pyprojectSpecifiers: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pyprojectSpecifiers = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExactPins(t *testing.T) {
+	lock := `version = 1
+requires-python = ">=3.12"
+
+[[package]]
+name = "HTTPX"
+version = "0.28.1"
+source = { registry = "https://pypi.org/simple" }
+dependencies = [
+    { name = "anyio" },
+]
+
+[[package]]
+name = "uvicorn"
+version = "0.32.1"
+source = { registry = "https://pypi.org/simple" }
+
+[package.optional-dependencies]
+standard = [
+    { name = "httptools" },
+]
+
+[[package]]
+name = "numpy"
+version = "2.0.2"
+resolution-markers = ["python_full_version < '3.13'"]
+
+[[package]]
+name = "numpy"
+version = "2.2.1"
+resolution-markers = ["python_full_version >= '3.13'"]
+`
+	path := filepath.Join(t.TempDir(), "uv.lock")
+	if err := os.WriteFile(path, []byte(lock), 0644); err != nil {
+		t.Fatal(err)
+	}
+	locked, err := lockedVersions(path)
+	if err != nil {
+		t.Fatalf("lockedVersions: %v", err)
+	}
+
+	got, err := exactPins([]string{"httpx", "uvicorn[standard]>=0.30; python_version < '3.14'"}, locked)
+	if err != nil {
+		t.Fatalf("exactPins: %v", err)
+	}
+	want := []string{"httpx==0.28.1", "uvicorn[standard]==0.32.1; python_version < '3.14'"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("exactPins = %q, want %q", got, want)
+	}
+
+	for _, tt := range []struct{ req, wantErr string }{
+		{"numpy", "numpy is locked at several versions"},
+		{"flask", "flask is not in uv.lock"},
+		{"eel @ https://example.com/eel.whl", "cannot pin eel: it is a direct reference"},
+	} {
+		if _, err := exactPins([]string{tt.req}, locked); err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+			t.Errorf("exactPins(%q) error = %v, want %q", tt.req, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"eel-cli/internal/config"
+	"eel-cli/pkg/utils"
+
+	"github.com/urfave/cli/v3"
+)
+
+func RunCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "run",
+		Usage:     "Run a script from eel.cli.json, or list them when no name is given",
+		ArgsUsage: "[script] [args...]",
+		// Everything after the script name belongs to the script.
+		SkipFlagParsing: true,
+		Action: func(c context.Context, cmd *cli.Command) error {
+			args := cmd.Args().Slice()
+			if len(args) == 0 {
+				return listScripts()
+			}
+			if args[0] == "-h" || args[0] == "--help" {
+				return cli.ShowSubcommandHelp(cmd)
+			}
+
+			extra := args[1:]
+			if len(extra) > 0 && extra[0] == "--" {
+				extra = extra[1:]
+			}
+			return runScript(c, args[0], extra)
+		},
+	}
+}
+
+func listScripts() error {
+	p, err := loadProject()
+	if err != nil {
+		return err
+	}
+
+	if len(p.Config.Scripts) == 0 {
+		fmt.Printf("No scripts defined. Add them under \"scripts\" in %s\n", config.FileName)
+		return nil
+	}
+
+	names := make([]string, 0, len(p.Config.Scripts))
+	for name := range p.Config.Scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		script := p.Config.Scripts[name]
+		summary := script.Description
+		if summary == "" {
+			summary = script.Command
+		}
+		if len(script.DependsOn) > 0 {
+			summary = strings.TrimSpace(summary + " (after " + strings.Join(script.DependsOn, ", ") + ")")
+		}
+		fmt.Fprintf(w, "%s\t%s\n", name, summary)
+	}
+	return w.Flush()
+}
+
+// runScript runs name after its dependencies, each once, in the project root.
+// args go to name only.
+func runScript(ctx context.Context, name string, args []string) error {
+	logger := utils.NewLogger()
+	executor := utils.NewExecutor()
+
+	p, err := loadProject()
+	if err != nil {
+		return err
+	}
+
+	order, err := scriptOrder(p.Config.Scripts, name)
+	if err != nil {
+		return err
+	}
+
+	env, err := p.ShellEnv("development")
+	if err != nil {
+		return err
+	}
+
+	for _, current := range order {
+		script := p.Config.Scripts[current]
+		if script.Command == "" {
+			continue
+		}
+
+		var scriptArgs []string
+		if current == name {
+			scriptArgs = args
+		}
+
+		logger.Info("Running script %s", current)
+		if err := executor.RunShellEnv(ctx, p.Dir, env, script.Command, scriptArgs...); err != nil {
+			return fmt.Errorf("script %s failed: %v", current, err)
+		}
+	}
+
+	return nil
+}
+
+// scriptOrder returns name and everything it depends on, dependencies first.
+func scriptOrder(scripts map[string]config.ScriptConfig, name string) ([]string, error) {
+	if _, ok := scripts[name]; !ok {
+		return nil, fmt.Errorf("unknown script: %s. Run eel run to list the available scripts", name)
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[string]int{}
+	var order, path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("script dependency cycle: %s -> %s", strings.Join(path, " -> "), name)
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range scripts[name].DependsOn {
+			if _, ok := scripts[dep]; !ok {
+				return fmt.Errorf("script %s depends on unknown script %s", name, dep)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+		order = append(order, name)
+		return nil
+	}
+
+	if err := visit(name); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
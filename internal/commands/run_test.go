@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"eel-cli/internal/config"
+)
+
+func TestScriptOrder(t *testing.T) {
+	script := func(dependsOn ...string) config.ScriptConfig {
+		return config.ScriptConfig{Command: "true", DependsOn: dependsOn}
+	}
+
+	tests := []struct {
+		name    string
+		scripts map[string]config.ScriptConfig
+		run     string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "no dependencies",
+			scripts: map[string]config.ScriptConfig{"a": script()},
+			run:     "a",
+			want:    []string{"a"},
+		},
+		{
+			name:    "dependencies run first, in declared order",
+			scripts: map[string]config.ScriptConfig{"release": script("lint", "test"), "lint": script(), "test": script()},
+			run:     "release",
+			want:    []string{"lint", "test", "release"},
+		},
+		{
+			name: "shared dependencies run once",
+			scripts: map[string]config.ScriptConfig{
+				"release": script("build", "test"),
+				"build":   script("gen"),
+				"test":    script("gen"),
+				"gen":     script(),
+			},
+			run:  "release",
+			want: []string{"gen", "build", "test", "release"},
+		},
+		{
+			name:    "scripts outside the graph are left out",
+			scripts: map[string]config.ScriptConfig{"a": script("b"), "b": script(), "c": script()},
+			run:     "a",
+			want:    []string{"b", "a"},
+		},
+		{
+			name:    "unknown script",
+			scripts: map[string]config.ScriptConfig{"a": script()},
+			run:     "b",
+			wantErr: "unknown script: b. Run eel run to list the available scripts",
+		},
+		{
+			name:    "unknown dependency",
+			scripts: map[string]config.ScriptConfig{"a": script("b"), "b": script("missing")},
+			run:     "a",
+			wantErr: "script b depends on unknown script missing",
+		},
+		{
+			name:    "script depending on itself",
+			scripts: map[string]config.ScriptConfig{"a": script("a")},
+			run:     "a",
+			wantErr: "script dependency cycle: a -> a",
+		},
+		{
+			name:    "cycle through the started script",
+			scripts: map[string]config.ScriptConfig{"a": script("b"), "b": script("c"), "c": script("a")},
+			run:     "a",
+			wantErr: "script dependency cycle: a -> b -> c -> a",
+		},
+		{
+			name:    "cycle further down",
+			scripts: map[string]config.ScriptConfig{"a": script("b"), "b": script("c"), "c": script("b")},
+			run:     "a",
+			wantErr: "script dependency cycle: a -> b -> c -> b",
+		},
+		{
+			name:    "cycle after a finished branch",
+			scripts: map[string]config.ScriptConfig{"a": script("b", "c"), "b": script(), "c": script("d"), "d": script("c")},
+			run:     "a",
+			wantErr: "script dependency cycle: a -> c -> d -> c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scriptOrder(tt.scripts, tt.run)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("scriptOrder error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("scriptOrder: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scriptOrder = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
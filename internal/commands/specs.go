@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	npmNamePattern  = regexp.MustCompile(`^(@[a-z0-9-~][a-z0-9-._~]*/)?[a-z0-9-~][a-z0-9-._~]*$`)
+	pyNamePattern   = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?$`)
+	pyVersionClause = regexp.MustCompile(`^(~=|===|==|!=|<=|>=|<|>)\s*[A-Za-z0-9.*+!_-]+$`)
+)
+
+// npmSourcePrefixes mark specifiers that install from somewhere other than
+// the registry; they are passed through unchecked.
+var npmSourcePrefixes = []string{".", "/", "~/", "file:", "link:", "git+", "git:", "github:", "gitlab:", "bitbucket:", "http:", "https:", "workspace:"}
+
+// validateWebSpec checks an npm package specifier: name, @scope/name, or
+// either with an @version, range or tag.
+func validateWebSpec(spec string, allowVersion bool) error {
+	for _, prefix := range npmSourcePrefixes {
+		if allowVersion && strings.HasPrefix(spec, prefix) {
+			return nil
+		}
+	}
+
+	name, version := spec, ""
+	if at := strings.LastIndex(spec, "@"); at > 0 {
+		name, version = spec[:at], spec[at+1:]
+		if version == "" {
+			return fmt.Errorf("invalid package %q: empty version after @", spec)
+		}
+	}
+
+	if !npmNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid package name %q", name)
+	}
+	if version != "" && !allowVersion {
+		return fmt.Errorf("invalid package %q: give the name without a version", spec)
+	}
+	if strings.ContainsAny(version, " \t") {
+		return fmt.Errorf("invalid package %q: version must not contain spaces", spec)
+	}
+
+	return nil
+}
+
+// validatePythonSpec checks a PEP 508 requirement such as requests,
+// uvicorn[standard]>=0.30,<1 or pkg @ https://... (markers after ";" are
+// left to uv).
+func validatePythonSpec(spec string, allowVersion bool) error {
+	req, _, _ := strings.Cut(spec, ";")
+	req = strings.TrimSpace(req)
+
+	if name, url, ok := strings.Cut(req, "@"); ok {
+		if !allowVersion {
+			return fmt.Errorf("invalid package %q: give the name without a URL", spec)
+		}
+		if strings.TrimSpace(url) == "" {
+			return fmt.Errorf("invalid package %q: empty URL after @", spec)
+		}
+		req = name
+	}
+
+	name := req
+	rest := ""
+	if idx := strings.IndexAny(req, "[~=!<>"); idx >= 0 {
+		name, rest = req[:idx], req[idx:]
+	}
+	name = strings.TrimSpace(name)
+	if !pyNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid package name %q", name)
+	}
+
+	if strings.HasPrefix(rest, "[") {
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return fmt.Errorf("invalid package %q: unclosed extras", spec)
+		}
+		for _, extra := range strings.Split(rest[1:end], ",") {
+			if !pyNamePattern.MatchString(strings.TrimSpace(extra)) {
+				return fmt.Errorf("invalid extra %q in %q", strings.TrimSpace(extra), spec)
+			}
+		}
+		rest = rest[end+1:]
+	}
+
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return nil
+	}
+	if !allowVersion {
+		return fmt.Errorf("invalid package %q: give the name without a version", spec)
+	}
+
+	for _, clause := range strings.Split(rest, ",") {
+		if !pyVersionClause.MatchString(strings.TrimSpace(clause)) {
+			return fmt.Errorf("invalid version specifier %q in %q", strings.TrimSpace(clause), spec)
+		}
+	}
+
+	return nil
+}
+
+func validateSpecs(specs []string, validate func(string, bool) error, allowVersion bool) error {
+	for _, spec := range specs {
+		if err := validate(spec, allowVersion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
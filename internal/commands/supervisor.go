@@ -0,0 +1,235 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"eel-cli/pkg/utils"
+)
+
+const (
+	restartOnFailure = "on-failure"
+	restartAlways    = "always"
+	restartNever     = "never"
+
+	restartMinBackoff = 500 * time.Millisecond
+	restartMaxBackoff = 10 * time.Second
+	// A process that stayed up this long is considered healthy again and
+	// its backoff is reset.
+	restartStableAfter = 10 * time.Second
+)
+
+func isValidRestartPolicy(policy string) bool {
+	return policy == "" || policy == restartOnFailure || policy == restartAlways || policy == restartNever
+}
+
+// devProcess describes a supervised dev child. command is called for every
+// (re)start because an exec.Cmd can only be run once.
+type devProcess struct {
+	name    string
+	policy  string
+	command func() (*exec.Cmd, error)
+
+	proc      *utils.Process
+	startedAt time.Time
+	backoff   time.Duration
+}
+
+type processExit struct {
+	dp   *devProcess
+	proc *utils.Process
+}
+
+type supervisor struct {
+	logger    *utils.Logger
+	procs     *utils.ProcessSet
+	processes map[string]*devProcess
+	exits     chan processExit
+	restarts  chan *devProcess
+	browseURL string
+}
+
+func newSupervisor(procs *utils.ProcessSet, logger *utils.Logger) *supervisor {
+	return &supervisor{
+		logger:    logger,
+		procs:     procs,
+		processes: map[string]*devProcess{},
+		exits:     make(chan processExit, 4),
+		restarts:  make(chan *devProcess, 4),
+	}
+}
+
+func (s *supervisor) Start(dp *devProcess) (*utils.Process, error) {
+	if dp.policy == "" {
+		dp.policy = restartNever
+	}
+	s.processes[dp.name] = dp
+
+	if err := s.start(dp); err != nil {
+		return nil, err
+	}
+	return dp.proc, nil
+}
+
+func (s *supervisor) start(dp *devProcess) error {
+	cmd, err := dp.command()
+	if err != nil {
+		return err
+	}
+
+	proc, err := utils.StartProcess(dp.name, cmd)
+	if err != nil {
+		return fmt.Errorf("failed to start %s: %v", dp.name, err)
+	}
+
+	dp.proc = proc
+	dp.startedAt = time.Now()
+	s.procs.Add(proc)
+
+	go func() {
+		<-proc.Done()
+		s.exits <- processExit{dp: dp, proc: proc}
+	}()
+
+	return nil
+}
+
+func (s *supervisor) restart(dp *devProcess) {
+	if dp.proc != nil {
+		old := dp.proc
+		dp.proc = nil
+		old.Stop(devShutdownGrace)
+		s.procs.Remove(old)
+	}
+
+	if err := s.start(dp); err != nil {
+		s.logger.Error("%v", err)
+	}
+}
+
+// Run supervises the started processes until the context is cancelled, the
+// user quits, or a process exits and its policy does not restart it.
+func (s *supervisor) Run(ctx context.Context, keys <-chan byte) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case exit := <-s.exits:
+			if exit.dp.proc != exit.proc {
+				// Stopped on purpose by a manual restart.
+				continue
+			}
+			s.procs.Remove(exit.proc)
+			if !s.handleExit(exit.dp, exit.proc.Err()) {
+				return
+			}
+
+		case dp := <-s.restarts:
+			if dp.proc == nil {
+				s.logger.Info("Restarting %s...", dp.name)
+				if err := s.start(dp); err != nil {
+					s.logger.Error("%v", err)
+					return
+				}
+			}
+
+		case key, ok := <-keys:
+			if !ok {
+				keys = nil
+				continue
+			}
+			if !s.handleKey(key) {
+				return
+			}
+		}
+	}
+}
+
+func (s *supervisor) handleExit(dp *devProcess, err error) bool {
+	dp.proc = nil
+
+	if err != nil {
+		s.logger.Warning("Process %s exited with error: %v", dp.name, err)
+	} else {
+		s.logger.Info("Process %s exited", dp.name)
+	}
+
+	if dp.policy == restartNever || (dp.policy == restartOnFailure && err == nil) {
+		return false
+	}
+
+	if time.Since(dp.startedAt) >= restartStableAfter || dp.backoff == 0 {
+		dp.backoff = restartMinBackoff
+	} else {
+		dp.backoff = min(dp.backoff*2, restartMaxBackoff)
+	}
+
+	s.logger.Info("Restarting %s in %s (policy: %s)", dp.name, dp.backoff, dp.policy)
+	time.AfterFunc(dp.backoff, func() {
+		s.restarts <- dp
+	})
+
+	return true
+}
+
+func (s *supervisor) handleKey(key byte) bool {
+	switch key {
+	case 'r', 'R':
+		if dp, ok := s.processes["py"]; ok {
+			s.logger.Info("Restarting py...")
+			dp.backoff = 0
+			s.restart(dp)
+		}
+	case 'o', 'O':
+		if s.browseURL == "" {
+			s.logger.Warning("No dev server URL to open in this mode")
+		} else if err := utils.OpenBrowser(s.browseURL); err != nil {
+			s.logger.Warning("Failed to open browser: %v", err)
+		}
+	case 'q', 'Q':
+		s.logger.Info("Shutting down development server...")
+		return false
+	case 'h', 'H', '?':
+		printDevKeys(s.logger)
+	}
+
+	return true
+}
+
+func printDevKeys(logger *utils.Logger) {
+	logger.Info("Keys: r = restart python, o = open browser, q = quit, h = help")
+}
+
+// readKeys streams single keypresses from stdin while it is a terminal. The
+// returned function restores the terminal.
+func readKeys() (<-chan byte, func()) {
+	if !utils.IsTerminal(os.Stdin) {
+		return nil, func() {}
+	}
+
+	restore, err := utils.EnableKeyInput(os.Stdin)
+	if err != nil {
+		return nil, func() {}
+	}
+
+	keys := make(chan byte)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 1)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				return
+			}
+			if n == 1 {
+				keys <- buf[0]
+			}
+		}
+	}()
+
+	return keys, restore
+}
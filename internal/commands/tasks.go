@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"eel-cli/pkg/utils"
+)
+
+// task is one step of a command that can run next to others, such as the
+// Python and the web install.
+type task struct {
+	name string
+	// title is logged before the task when tasks run one after another.
+	title string
+	run   func(ctx context.Context, executor *utils.Executor) error
+}
+
+type taskResult struct {
+	name      string
+	err       error
+	cancelled bool
+	duration  time.Duration
+}
+
+// runTasksSequential runs tasks one after another with their output going
+// straight to the terminal, stopping at the first failure.
+func runTasksSequential(ctx context.Context, tasks []task) []taskResult {
+	logger := utils.NewLogger()
+	executor := utils.NewExecutor()
+
+	var results []taskResult
+	for _, t := range tasks {
+		logger.Info("%s...", t.title)
+		start := time.Now()
+		err := t.run(ctx, executor)
+		results = append(results, taskResult{name: t.name, err: err, duration: time.Since(start)})
+		if err != nil {
+			break
+		}
+	}
+
+	return results
+}
+
+// runTasksParallel runs tasks concurrently, each printing through its own
+// line-buffered, name-prefixed stream. When failFast is set the first
+// failure cancels the tasks still running. Every command runs in its own
+// process group, which cancelling stops as a whole; Ctrl+C cancels too.
+func runTasksParallel(ctx context.Context, tasks []task, failFast bool) []taskResult {
+	ctx, stopSignals := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	mux := utils.NewMultiplexer(os.Stdout, utils.MuxOptions{
+		Color: os.Getenv("NO_COLOR") == "" && utils.IsTerminal(os.Stdout),
+	})
+	defer mux.Close()
+
+	results := make([]taskResult, len(tasks))
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+	)
+
+	for i, t := range tasks {
+		results[i].name = t.name
+
+		out, err := mux.Stream(t.name)
+		if err != nil {
+			results[i].err = err
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer out.Close()
+
+			start := time.Now()
+			err := t.run(ctx, utils.NewExecutor().WithOutput(out).WithProcessGroups())
+			results[i].duration = time.Since(start)
+			if err == nil {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if failed && ctx.Err() != nil {
+				results[i].cancelled = true
+				return
+			}
+			results[i].err = err
+			if failFast {
+				failed = true
+				cancel()
+			}
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+// printTaskSummary prints one line per task with its duration and returns
+// an error naming every failed task.
+func printTaskSummary(logger *utils.Logger, results []taskResult) error {
+	var failed []taskResult
+
+	for _, r := range results {
+		duration := r.duration.Round(100 * time.Millisecond)
+		switch {
+		case r.cancelled:
+			logger.Warning("%s cancelled after %s", r.name, duration)
+		case r.err != nil:
+			logger.Error("%s failed after %s", r.name, duration)
+			failed = append(failed, r)
+		default:
+			logger.Success("%s finished in %s", r.name, duration)
+		}
+	}
+
+	switch len(failed) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("%s failed: %v", failed[0].name, failed[0].err)
+	}
+
+	names := make([]string, len(failed))
+	for i, r := range failed {
+		names[i] = r.name
+	}
+	return fmt.Errorf("%s failed", strings.Join(names, ", "))
+}
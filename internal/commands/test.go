@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"eel-cli/pkg/utils"
+
+	"github.com/urfave/cli/v3"
+)
+
+const testReportsDir = "reports"
+
+func TestCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "test",
+		Usage: "Run pytest and the web test script, collecting JUnit reports in reports/",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "python-only",
+				Usage: "Only run the Python tests",
+			},
+			&cli.BoolFlag{
+				Name:  "web-only",
+				Usage: "Only run the web tests",
+			},
+			&cli.BoolFlag{
+				Name:    "watch",
+				Usage:   "Re-run the tests when files change",
+				Aliases: []string{"w"},
+			},
+		},
+		Action: func(c context.Context, cmd *cli.Command) error {
+			pythonOnly, webOnly := cmd.Bool("python-only"), cmd.Bool("web-only")
+			if pythonOnly && webOnly {
+				return fmt.Errorf("--python-only and --web-only are mutually exclusive")
+			}
+
+			return testProject(c, !webOnly, !pythonOnly, cmd.Bool("watch"))
+		},
+	}
+}
+
+func testProject(ctx context.Context, python, web, watch bool) error {
+	logger := utils.NewLogger()
+
+	p, err := loadProject()
+	if err != nil {
+		return err
+	}
+
+	reportsDir := p.path(testReportsDir)
+	if err := os.MkdirAll(reportsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", testReportsDir, err)
+	}
+
+	var tasks []task
+	if python {
+		hasPython, err := checkPythonTools(p)
+		if err != nil {
+			return err
+		}
+		if hasPython {
+			tasks = append(tasks, pytestTask(p.Dir, filepath.Join(reportsDir, "pytest.xml"), watch))
+		}
+	}
+
+	if web {
+		t, ok, err := webTestTask(p, filepath.Join(reportsDir, "web.xml"), watch)
+		if err != nil {
+			return err
+		} else if ok {
+			tasks = append(tasks, t)
+		} else {
+			logger.Info("No test script in package.json, skipping web")
+		}
+	}
+
+	if len(tasks) == 0 {
+		return fmt.Errorf("nothing to test: no pyproject.toml and no test script in package.json")
+	}
+
+	err = printTaskSummary(logger, runTasksParallel(ctx, tasks, false))
+	logger.Info("JUnit reports are in %s/", testReportsDir)
+	return err
+}
+
+// pytestTask runs pytest, or pytest-watcher (added on the fly like debugpy)
+// in watch mode. ptw forwards the arguments it doesn't know to pytest.
+func pytestTask(projectDir, report string, watch bool) task {
+	args := []string{"run", "pytest"}
+	if watch {
+		args = []string{"run", "--with", "pytest-watcher", "ptw", "."}
+	}
+	args = append(args, "--junitxml", report)
+
+	return task{
+		name:  "pytest",
+		title: "Running pytest",
+		run: func(ctx context.Context, executor *utils.Executor) error {
+			// A report from an earlier run would pass for this one's.
+			os.Remove(report)
+			return executor.RunCommand(ctx, projectDir, "uv", args...)
+		},
+	}
+}
+
+// webTestTask runs the test script with the options its runner needs for a
+// JUnit report and for watch mode. Vitest and Jest (with jest-junit) are
+// recognized; other runners are run as they are.
+func webTestTask(p *project, report string, watch bool) (task, bool, error) {
+	logger := utils.NewLogger()
+
+	webDir := p.WebDir()
+	pkg, err := readPackageJSON(filepath.Join(webDir, "package.json"))
+	if os.IsNotExist(err) {
+		return task{}, false, nil
+	} else if err != nil {
+		return task{}, false, err
+	}
+	script := pkg.Scripts["test"]
+	if script == "" {
+		return task{}, false, nil
+	}
+
+	manager, err := p.PackageManager()
+	if err != nil {
+		return task{}, false, err
+	}
+
+	var args []string
+	env := os.Environ()
+	switch testRunner(script) {
+	case "vitest":
+		if watch {
+			args = append(args, "--watch")
+		} else {
+			args = append(args, "--run")
+		}
+		args = append(args, "--reporter=default", "--reporter=junit", "--outputFile.junit="+report)
+	case "jest":
+		if watch {
+			args = append(args, "--watch")
+		}
+		if pkg.DevDependencies["jest-junit"] != "" || pkg.Dependencies["jest-junit"] != "" {
+			args = append(args, "--reporters=default", "--reporters=jest-junit")
+			env = append(env, "JEST_JUNIT_OUTPUT_FILE="+report)
+		} else {
+			logger.Warning("Add jest-junit to the web devDependencies to get a JUnit report from jest")
+		}
+	default:
+		logger.Warning("Unknown web test runner, no JUnit report is collected from it")
+		if watch {
+			logger.Warning("Watch mode is not supported for the web test script")
+		}
+	}
+
+	return task{
+		name:  "web",
+		title: "Running the web test script",
+		run: func(ctx context.Context, executor *utils.Executor) error {
+			os.Remove(report)
+			return executor.RunCommandEnv(ctx, webDir, env, manager.Name(), manager.Run("test", args...)...)
+		},
+	}, true, nil
+}
+
+// testRunner returns "vitest" or "jest" when the script invokes one of them.
+func testRunner(script string) string {
+	for _, field := range strings.Fields(script) {
+		switch filepath.Base(field) {
+		case "vitest", "jest":
+			return filepath.Base(field)
+		}
+	}
+	return ""
+}
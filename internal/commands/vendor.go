@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"eel-cli/internal/config"
+	"eel-cli/pkg/utils"
+
+	"github.com/urfave/cli/v3"
+)
+
+func VendorCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "vendor",
+		Usage: "Download Python and web packages into the project for eel install --offline",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "uv-cache",
+				Usage: "Directory for the uv cache, relative to the project root",
+			},
+			&cli.StringFlag{
+				Name:  "web-cache",
+				Usage: "Directory for the web package cache, relative to the project root",
+			},
+		},
+		Action: func(c context.Context, cmd *cli.Command) error {
+			return vendorDependencies(c, cmd.String("uv-cache"), cmd.String("web-cache"))
+		},
+	}
+}
+
+func vendorDependencies(ctx context.Context, uvCache, webCache string) error {
+	logger := utils.NewLogger()
+	executor := utils.NewExecutor()
+
+	p, err := loadEelProject()
+	if err != nil {
+		return err
+	}
+
+	if !executor.CommandExists("uv") {
+		return fmt.Errorf("uv is not installed. Please install it first")
+	}
+
+	vendor := p.Config.Vendor
+	defaults := config.DefaultVendor()
+	if uvCache != "" {
+		vendor.UVCache = uvCache
+	} else if vendor.UVCache == "" {
+		vendor.UVCache = defaults.UVCache
+	}
+	if webCache != "" {
+		vendor.WebCache = webCache
+	} else if vendor.WebCache == "" {
+		vendor.WebCache = defaults.WebCache
+	}
+
+	uvCacheDir := p.path(vendor.UVCache)
+	tasks := []task{{
+		name:  "python",
+		title: "Caching Python packages",
+		run: func(ctx context.Context, executor *utils.Executor) error {
+			return vendorPythonPackages(ctx, executor, p.Dir, uvCacheDir)
+		},
+	}}
+
+	webDir := p.WebDir()
+	hasWeb := p.HasWebBuild()
+	if hasWeb {
+		manager, err := p.PackageManager()
+		if err != nil {
+			return err
+		}
+		webCacheDir := p.path(vendor.WebCache)
+		tasks = append(tasks, task{
+			name:  "web",
+			title: "Caching web packages",
+			run: func(ctx context.Context, executor *utils.Executor) error {
+				if err := os.MkdirAll(webCacheDir, 0755); err != nil {
+					return err
+				}
+				env := append(os.Environ(), manager.CacheEnv(webCacheDir)...)
+				return executor.RunCommandEnv(ctx, webDir, env, manager.Name(), manager.Vendor(webCacheDir)...)
+			},
+		})
+	} else {
+		vendor.WebCache = ""
+	}
+
+	if err := printTaskSummary(logger, runTasksParallel(ctx, tasks, true)); err != nil {
+		return fmt.Errorf("failed to vendor dependencies: %v", err)
+	}
+
+	if vendor != p.Config.Vendor {
+		p.Config.Vendor = vendor
+		if err := config.SaveConfig(p.Dir, p.Config); err != nil {
+			return fmt.Errorf("failed to save config: %v", err)
+		}
+		logger.Info("Recorded vendor directories in %s", config.FileName)
+	}
+
+	logger.Success("Dependencies vendored. Install them with: eel install --offline")
+	return nil
+}
+
+// vendorPythonPackages fills cacheDir with every package in uv.lock, all
+// extras and groups included, by syncing them into a throwaway environment.
+// uv sync --offline then finds them under the URLs the lockfile pins.
+func vendorPythonPackages(ctx context.Context, executor *utils.Executor, projectDir, cacheDir string) error {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return err
+	}
+
+	venv, err := os.MkdirTemp("", "eel-vendor-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(venv)
+
+	env := append(os.Environ(), "UV_PROJECT_ENVIRONMENT="+venv)
+	return executor.RunCommandEnv(ctx, projectDir, env, "uv", "sync", "--cache-dir", cacheDir, "--all-extras", "--all-groups")
+}
@@ -3,9 +3,9 @@ package commands
 import (
 	"context"
 	"fmt"
-	"path/filepath"
+	"strings"
 
-	"eel-cli/internal/config"
+	"eel-cli/internal/pm"
 	"eel-cli/pkg/utils"
 
 	"github.com/urfave/cli/v3"
@@ -17,172 +17,146 @@ func WebCommand() *cli.Command {
 		Usage: "Manage web packages",
 		Commands: []*cli.Command{
 			{
-				Name:  "add",
-				Usage: "Add a web package",
+				Name:      "add",
+				Usage:     "Add web packages",
+				ArgsUsage: "<package[@version]>...",
 				Flags: []cli.Flag{
 					&cli.BoolFlag{
 						Name:    "dev",
 						Usage:   "Add as dev dependency",
 						Aliases: []string{"d"},
 					},
+					&cli.BoolFlag{
+						Name:    "exact",
+						Usage:   "Save the exact version instead of a range",
+						Aliases: []string{"E"},
+					},
 				},
 				Action: func(c context.Context, cmd *cli.Command) error {
-					args := cmd.Args().Slice()
-					if len(args) == 0 {
+					packages := cmd.Args().Slice()
+					if len(packages) == 0 {
 						return fmt.Errorf("package name is required")
 					}
+					if err := validateSpecs(packages, validateWebSpec, true); err != nil {
+						return err
+					}
 
-					packageName := args[0]
-					isDev := cmd.Bool("dev")
-
-					return addWebPackage(packageName, isDev)
+					return addWebPackages(packages, pm.AddOptions{
+						Dev:   cmd.Bool("dev"),
+						Exact: cmd.Bool("exact"),
+					})
 				},
 			},
 			{
-				Name:  "remove",
-				Usage: "Remove a web package",
+				Name:      "remove",
+				Usage:     "Remove web packages",
+				ArgsUsage: "<package>...",
 				Action: func(c context.Context, cmd *cli.Command) error {
-					args := cmd.Args().Slice()
-					if len(args) == 0 {
+					packages := cmd.Args().Slice()
+					if len(packages) == 0 {
 						return fmt.Errorf("package name is required")
 					}
+					if err := validateSpecs(packages, validateWebSpec, false); err != nil {
+						return err
+					}
 
-					packageName := args[0]
-					return removeWebPackage(packageName)
+					return removeWebPackages(packages)
+				},
+			},
+			{
+				Name:  "list",
+				Usage: "List web dependencies",
+				Flags: []cli.Flag{jsonFlag()},
+				Action: func(c context.Context, cmd *cli.Command) error {
+					return listWebPackages(cmd.Bool("json"))
+				},
+			},
+			{
+				Name:  "outdated",
+				Usage: "List web dependencies with newer versions",
+				Flags: []cli.Flag{jsonFlag()},
+				Action: func(c context.Context, cmd *cli.Command) error {
+					return outdatedWebPackages(cmd.Bool("json"))
+				},
+			},
+			{
+				Name:      "upgrade",
+				Usage:     "Upgrade web packages within their ranges",
+				ArgsUsage: "[package]...",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "latest",
+						Usage: "Upgrade to the latest version, ignoring the declared range",
+					},
+				},
+				Action: func(c context.Context, cmd *cli.Command) error {
+					packages := cmd.Args().Slice()
+					if err := validateSpecs(packages, validateWebSpec, false); err != nil {
+						return err
+					}
+					return upgradeWebPackages(packages, cmd.Bool("latest"))
 				},
 			},
 		},
 	}
 }
 
-func addWebPackage(packageName string, isDev bool) error {
+func addWebPackages(packages []string, opts pm.AddOptions) error {
 	logger := utils.NewLogger()
 	executor := utils.NewExecutor()
 
-	projectDir, err := executor.GetWorkingDir()
+	p, err := loadProject()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %v", err)
+		return err
 	}
 
-	cfg, err := config.LoadConfig(projectDir)
+	webDir, err := p.RequireWebDir()
 	if err != nil {
-		return fmt.Errorf("failed to load config: %v", err)
+		return err
 	}
 
-	webDir := filepath.Join(projectDir, "web")
-	if !executor.DirExists(webDir) {
-		return fmt.Errorf("web directory not found")
-	}
-
-	manager := cfg.Manager
-	if manager == "" {
-		manager = detectPackageManager()
+	manager, err := p.PackageManager()
+	if err != nil {
+		return err
 	}
 
-	logger.Info("Adding web package: %s (dev: %v)", packageName, isDev)
+	logger.Info("Adding web packages: %s (dev: %v)", strings.Join(packages, ", "), opts.Dev)
 
 	ctx := context.Background()
-	var args []string
-
-	switch manager {
-	case "npm":
-		args = []string{"install"}
-		if isDev {
-			args = append(args, "--save-dev")
-		}
-		args = append(args, packageName)
-		err = executor.RunCommand(ctx, webDir, "npm", args...)
-
-	case "yarn":
-		args = []string{"add"}
-		if isDev {
-			args = append(args, "--dev")
-		}
-		args = append(args, packageName)
-		err = executor.RunCommand(ctx, webDir, "yarn", args...)
-
-	case "pnpm":
-		args = []string{"add"}
-		if isDev {
-			args = append(args, "--save-dev")
-		}
-		args = append(args, packageName)
-		err = executor.RunCommand(ctx, webDir, "pnpm", args...)
-
-	case "bun":
-		args = []string{"add"}
-		if isDev {
-			args = append(args, "--dev")
-		}
-		args = append(args, packageName)
-		err = executor.RunCommand(ctx, webDir, "bun", args...)
-
-	default:
-		return fmt.Errorf("unsupported package manager: %s", manager)
+	if err := executor.RunCommand(ctx, webDir, manager.Name(), manager.Add(packages, opts)...); err != nil {
+		return fmt.Errorf("failed to add packages: %v", err)
 	}
 
-	if err != nil {
-		return fmt.Errorf("failed to add package: %v", err)
-	}
-
-	logger.Success("Package %s added successfully", packageName)
+	logger.Success("Added %s", strings.Join(packages, ", "))
 	return nil
 }
 
-func removeWebPackage(packageName string) error {
+func removeWebPackages(packages []string) error {
 	logger := utils.NewLogger()
 	executor := utils.NewExecutor()
 
-	projectDir, err := executor.GetWorkingDir()
+	p, err := loadProject()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %v", err)
+		return err
 	}
 
-	cfg, err := config.LoadConfig(projectDir)
+	webDir, err := p.RequireWebDir()
 	if err != nil {
-		return fmt.Errorf("failed to load config: %v", err)
-	}
-
-	webDir := filepath.Join(projectDir, "web")
-	if !executor.DirExists(webDir) {
-		return fmt.Errorf("web directory not found")
+		return err
 	}
 
-	manager := cfg.Manager
-	if manager == "" {
-		manager = detectPackageManager()
+	manager, err := p.PackageManager()
+	if err != nil {
+		return err
 	}
 
-	logger.Info("Removing web package: %s", packageName)
+	logger.Info("Removing web packages: %s", strings.Join(packages, ", "))
 
 	ctx := context.Background()
-	var args []string
-
-	switch manager {
-	case "npm":
-		args = []string{"uninstall", packageName}
-		err = executor.RunCommand(ctx, webDir, "npm", args...)
-
-	case "yarn":
-		args = []string{"remove", packageName}
-		err = executor.RunCommand(ctx, webDir, "yarn", args...)
-
-	case "pnpm":
-		args = []string{"remove", packageName}
-		err = executor.RunCommand(ctx, webDir, "pnpm", args...)
-
-	case "bun":
-		args = []string{"remove", packageName}
-		err = executor.RunCommand(ctx, webDir, "bun", args...)
-
-	default:
-		return fmt.Errorf("unsupported package manager: %s", manager)
-	}
-
-	if err != nil {
-		return fmt.Errorf("failed to remove package: %v", err)
+	if err := executor.RunCommand(ctx, webDir, manager.Name(), manager.Remove(packages)...); err != nil {
+		return fmt.Errorf("failed to remove packages: %v", err)
 	}
 
-	logger.Success("Package %s removed successfully", packageName)
+	logger.Success("Removed %s", strings.Join(packages, ", "))
 	return nil
 }
@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"eel-cli/pkg/utils"
+)
+
+type packageJSON struct {
+	Version         string            `json:"version"`
+	PackageManager  string            `json:"packageManager"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+	Scripts         map[string]string `json:"scripts"`
+}
+
+func readPackageJSON(path string) (*packageJSON, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &pkg, nil
+}
+
+// listWebPackages reads the declared dependencies from package.json and the
+// installed versions from node_modules, so it works the same for every
+// package manager.
+func listWebPackages(asJSON bool) error {
+	p, err := loadProject()
+	if err != nil {
+		return err
+	}
+
+	webDir, err := p.RequireWebDir()
+	if err != nil {
+		return err
+	}
+
+	pkg, err := readPackageJSON(filepath.Join(webDir, "package.json"))
+	if err != nil {
+		return err
+	}
+
+	var deps []dependency
+	add := func(ranges map[string]string, depType string) {
+		for name, wanted := range ranges {
+			current := ""
+			if installed, err := readPackageJSON(filepath.Join(webDir, "node_modules", name, "package.json")); err == nil {
+				current = installed.Version
+			}
+			deps = append(deps, dependency{Name: name, Current: current, Wanted: wanted, Type: depType})
+		}
+	}
+	add(pkg.Dependencies, "prod")
+	add(pkg.DevDependencies, "dev")
+
+	return printDependencies(deps, asJSON, "No web dependencies")
+}
+
+func outdatedWebPackages(asJSON bool) error {
+	p, err := loadProject()
+	if err != nil {
+		return err
+	}
+
+	webDir, err := p.RequireWebDir()
+	if err != nil {
+		return err
+	}
+
+	manager, err := p.PackageManager()
+	if err != nil {
+		return err
+	}
+
+	args, err := manager.Outdated()
+	if err != nil {
+		return err
+	}
+
+	out, err := outdatedOutput(utils.NewExecutor().RunCommandOutput(context.Background(), webDir, manager.Name(), args...))
+	if err != nil {
+		return fmt.Errorf("failed to check web packages: %v", err)
+	}
+
+	outdated, err := manager.ParseOutdated(out)
+	if err != nil {
+		return fmt.Errorf("failed to check web packages: %v", err)
+	}
+
+	var deps []dependency
+	for _, o := range outdated {
+		depType := "prod"
+		if o.Dev {
+			depType = "dev"
+		}
+		deps = append(deps, dependency{Name: o.Name, Current: o.Current, Wanted: o.Wanted, Latest: o.Latest, Type: depType})
+	}
+
+	return printDependencies(deps, asJSON, "All web packages are up to date")
+}
+
+func upgradeWebPackages(packages []string, latest bool) error {
+	logger := utils.NewLogger()
+	executor := utils.NewExecutor()
+
+	p, err := loadProject()
+	if err != nil {
+		return err
+	}
+
+	webDir, err := p.RequireWebDir()
+	if err != nil {
+		return err
+	}
+
+	manager, err := p.PackageManager()
+	if err != nil {
+		return err
+	}
+
+	if len(packages) == 0 {
+		logger.Info("Upgrading web packages (latest: %v)", latest)
+	} else {
+		logger.Info("Upgrading web packages: %s (latest: %v)", strings.Join(packages, ", "), latest)
+	}
+
+	runs, err := manager.Upgrade(packages, latest)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	for _, args := range runs {
+		if err := executor.RunCommand(ctx, webDir, manager.Name(), args...); err != nil {
+			return fmt.Errorf("failed to upgrade packages: %v", err)
+		}
+	}
+
+	logger.Success("Web packages upgraded")
+	return nil
+}
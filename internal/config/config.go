@@ -7,13 +7,59 @@ import (
 )
 
 type Config struct {
-	Manager string      `json:"manager"`
-	Dev     DevConfig   `json:"dev"`
-	Build   BuildConfig `json:"build"`
+	Manager string                  `json:"manager"`
+	Paths   PathsConfig             `json:"paths"`
+	App     AppConfig               `json:"app,omitzero"`
+	Dev     DevConfig               `json:"dev"`
+	Build   BuildConfig             `json:"build"`
+	Vendor  VendorConfig            `json:"vendor,omitzero"`
+	Scripts map[string]ScriptConfig `json:"scripts,omitempty"`
+}
+
+type PathsConfig struct {
+	Entry     string `json:"entry"`
+	WebDir    string `json:"webDir"`
+	WebOutDir string `json:"webOutDir"`
+	DistDir   string `json:"distDir"`
+}
+
+// AppConfig controls how main.py starts Eel. The CLI passes it to the app as
+// EEL_APP_* environment variables; empty fields keep the app's defaults.
+type AppConfig struct {
+	// Mode is the browser mode: chrome, edge, default or none.
+	Mode string `json:"mode,omitempty"`
+	// Size is WIDTHxHEIGHT, Position is X,Y.
+	Size     string `json:"size,omitempty"`
+	Position string `json:"position,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Host     string `json:"host,omitempty"`
 }
 
 type DevConfig struct {
-	Mode string `json:"mode"`
+	Mode     string        `json:"mode"`
+	LogFiles bool          `json:"logFiles,omitempty"`
+	Restart  RestartConfig `json:"restart,omitzero"`
+	// ReadyTimeout is how long to wait for the Vite dev server, in seconds.
+	ReadyTimeout int         `json:"readyTimeout,omitempty"`
+	Debug        DebugConfig `json:"debug,omitzero"`
+	// ProxyPort is the port of the dev proxy that serves Vite and Eel from
+	// one origin in url mode.
+	ProxyPort int `json:"proxyPort,omitempty"`
+}
+
+// DebugConfig runs the backend under debugpy during eel dev.
+type DebugConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+	Port    int  `json:"port,omitempty"`
+	Wait    bool `json:"wait,omitempty"`
+}
+
+// RestartConfig holds per-process restart policies (on-failure, always,
+// never). In eel.cli.json it is either an object keyed by process name or a
+// single policy string applied to every process.
+type RestartConfig struct {
+	Py   string `json:"py,omitempty"`
+	Vite string `json:"vite,omitempty"`
 }
 
 type BuildConfig struct {
@@ -21,14 +67,66 @@ type BuildConfig struct {
 	Icon      string `json:"icon"`
 	NoConsole bool   `json:"noConsole"`
 	OneFile   bool   `json:"oneFile"`
+	// BakeEnv lists variables from .env/.env.production that are compiled
+	// into the frozen app.
+	BakeEnv []string `json:"bakeEnv,omitempty"`
+}
+
+// VendorConfig records where eel vendor put the packages that offline
+// installs use, relative to the project root.
+type VendorConfig struct {
+	// UVCache is a uv cache directory holding every package in uv.lock.
+	UVCache  string `json:"uvCache,omitempty"`
+	WebCache string `json:"webCache,omitempty"`
+}
+
+// DefaultVendor is where eel vendor writes when nothing is configured.
+func DefaultVendor() VendorConfig {
+	return VendorConfig{
+		UVCache:  "vendor/uv",
+		WebCache: "vendor/web",
+	}
+}
+
+// ScriptConfig is a task for eel run. In eel.cli.json it is either a command
+// string or an object that can also describe the task and list the scripts
+// it depends on.
+type ScriptConfig struct {
+	Command     string   `json:"command,omitempty"`
+	Description string   `json:"description,omitempty"`
+	DependsOn   []string `json:"dependsOn,omitempty"`
+}
+
+const FileName = "eel.cli.json"
+
+// FindProjectRoot walks up from dir to the nearest directory containing
+// eel.cli.json. It returns false when no parent has one.
+func FindProjectRoot(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		if info, err := os.Stat(filepath.Join(dir, FileName)); err == nil && !info.IsDir() {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
 }
 
 func LoadConfig(projectDir string) (*Config, error) {
-	configPath := filepath.Join(projectDir, "eel.cli.json")
+	configPath := filepath.Join(projectDir, FileName)
 
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		return &Config{
 			Manager: "",
+			Paths:   DefaultPaths(),
 			Dev: DevConfig{
 				Mode: "",
 			},
@@ -51,11 +149,71 @@ func LoadConfig(projectDir string) (*Config, error) {
 		return nil, err
 	}
 
+	config.Paths.applyDefaults()
+
 	return &config, nil
 }
 
+func (r *RestartConfig) UnmarshalJSON(data []byte) error {
+	var policy string
+	if err := json.Unmarshal(data, &policy); err == nil {
+		r.Py = policy
+		r.Vite = policy
+		return nil
+	}
+
+	type restartConfig RestartConfig
+	return json.Unmarshal(data, (*restartConfig)(r))
+}
+
+func (s *ScriptConfig) UnmarshalJSON(data []byte) error {
+	var command string
+	if err := json.Unmarshal(data, &command); err == nil {
+		*s = ScriptConfig{Command: command}
+		return nil
+	}
+
+	type scriptConfig ScriptConfig
+	return json.Unmarshal(data, (*scriptConfig)(s))
+}
+
+// MarshalJSON writes plain commands back in the short string form.
+func (s ScriptConfig) MarshalJSON() ([]byte, error) {
+	if s.Description == "" && len(s.DependsOn) == 0 {
+		return json.Marshal(s.Command)
+	}
+
+	type scriptConfig ScriptConfig
+	return json.Marshal(scriptConfig(s))
+}
+
+func DefaultPaths() PathsConfig {
+	return PathsConfig{
+		Entry:     "main.py",
+		WebDir:    "web",
+		WebOutDir: ".distweb",
+		DistDir:   "dist",
+	}
+}
+
+func (p *PathsConfig) applyDefaults() {
+	defaults := DefaultPaths()
+	if p.Entry == "" {
+		p.Entry = defaults.Entry
+	}
+	if p.WebDir == "" {
+		p.WebDir = defaults.WebDir
+	}
+	if p.WebOutDir == "" {
+		p.WebOutDir = defaults.WebOutDir
+	}
+	if p.DistDir == "" {
+		p.DistDir = defaults.DistDir
+	}
+}
+
 func SaveConfig(projectDir string, config *Config) error {
-	configPath := filepath.Join(projectDir, "eel.cli.json")
+	configPath := filepath.Join(projectDir, FileName)
 
 	data, err := json.MarshalIndent(config, "", "    ")
 	if err != nil {
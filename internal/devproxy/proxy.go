@@ -0,0 +1,113 @@
+// Package devproxy serves the Vite dev server and the Eel backend from a
+// single origin, so the page, /eel.js and the /eel websocket share a host
+// during development.
+package devproxy
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync/atomic"
+	"time"
+)
+
+// eelPaths are served by the Eel backend; everything else, including Vite's
+// HMR websocket, goes to Vite.
+var eelPaths = map[string]bool{
+	"/eel.js": true,
+	"/eel":    true,
+}
+
+type Proxy struct {
+	listener net.Listener
+	server   *http.Server
+	vite     atomic.Pointer[url.URL]
+	eel      *url.URL
+}
+
+// Listen binds the proxy to addr (use port 0 for a free port). Call Serve to
+// start handling requests.
+func Listen(addr, viteURL, eelURL string) (*Proxy, error) {
+	p := &Proxy{}
+
+	if err := p.SetVite(viteURL); err != nil {
+		return nil, err
+	}
+
+	eel, err := url.Parse(eelURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Eel URL %s: %v", eelURL, err)
+	}
+	p.eel = eel
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	p.listener = listener
+
+	p.server = &http.Server{
+		Handler:           p.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return p, nil
+}
+
+// SetVite points the proxy at a new Vite URL, e.g. after Vite restarted on
+// another port.
+func (p *Proxy) SetVite(viteURL string) error {
+	u, err := url.Parse(viteURL)
+	if err != nil {
+		return fmt.Errorf("invalid Vite URL %s: %v", viteURL, err)
+	}
+	p.vite.Store(u)
+	return nil
+}
+
+func (p *Proxy) Port() int {
+	return p.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (p *Proxy) URL() string {
+	return fmt.Sprintf("http://localhost:%d", p.Port())
+}
+
+// Serve handles requests until Close is called.
+func (p *Proxy) Serve() error {
+	if err := p.server.Serve(p.listener); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func (p *Proxy) Close() error {
+	return p.server.Close()
+}
+
+func (p *Proxy) handler() http.Handler {
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			target := p.vite.Load()
+			if eelPaths[r.In.URL.Path] {
+				target = p.eel
+			}
+			r.SetURL(target)
+			// Keep the browser's Host so Vite and Eel build URLs for the
+			// proxy origin.
+			r.Out.Host = r.In.Host
+		},
+		FlushInterval: -1,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			backend := "Vite"
+			if eelPaths[r.URL.Path] {
+				backend = "Eel"
+			}
+			http.Error(w, fmt.Sprintf("eel dev proxy: %s is not reachable: %v", backend, err), http.StatusBadGateway)
+		},
+	}
+
+	return proxy
+}
@@ -0,0 +1,93 @@
+package pm
+
+import (
+	"regexp"
+	"strings"
+)
+
+type bun struct{}
+
+func (m *bun) Name() string { return "bun" }
+
+func (m *bun) Lockfiles() []string { return []string{"bun.lock", "bun.lockb"} }
+
+func (m *bun) Install() []string { return []string{"install"} }
+
+func (m *bun) FrozenInstall() []string { return []string{"install", "--frozen-lockfile"} }
+
+func (m *bun) Vendor(cacheDir string) []string {
+	return []string{"install", "--cache-dir", cacheDir}
+}
+
+// OfflineInstall uses --prefer-offline: bun has no strict offline mode, but
+// with everything cached it does not need the registry.
+func (m *bun) OfflineInstall(cacheDir string, frozen bool) []string {
+	return join([]string{"install", "--prefer-offline"}, flag(frozen, "--frozen-lockfile"), option("--cache-dir", cacheDir))
+}
+
+func (m *bun) CacheEnv(cacheDir string) []string { return nil }
+
+func (m *bun) Add(packages []string, opts AddOptions) []string {
+	return join([]string{"add"}, flag(opts.Dev, "--dev"), flag(opts.Exact, "--exact"), packages)
+}
+
+func (m *bun) Remove(packages []string) []string {
+	return join([]string{"remove"}, packages)
+}
+
+func (m *bun) Run(script string, args ...string) []string {
+	return join([]string{"run", script}, args)
+}
+
+func (m *bun) Exec(bin string, args ...string) []string {
+	return join([]string{"x", bin}, args)
+}
+
+func (m *bun) Upgrade(packages []string, latest bool) ([][]string, error) {
+	return [][]string{join([]string{"update"}, packages, flag(latest, "--latest"))}, nil
+}
+
+func (m *bun) Outdated() ([]string, error) {
+	return []string{"outdated"}, nil
+}
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+// ParseOutdated reads the table printed by `bun outdated`, drawn with either
+// ASCII or box-drawing borders:
+//
+//	│ Package          │ Current │ Update │ Latest │
+//	│ @types/bun (dev) │ 1.1.0   │ 1.1.8  │ 1.1.8  │
+func (m *bun) ParseOutdated(out string) ([]Outdated, error) {
+	var outdated []Outdated
+
+	for _, line := range strings.Split(ansiEscape.ReplaceAllString(out, ""), "\n") {
+		line = strings.TrimSpace(strings.ReplaceAll(line, "│", "|"))
+		if !strings.HasPrefix(line, "|") {
+			continue
+		}
+
+		cells := strings.Split(strings.Trim(line, "|"), "|")
+		if len(cells) < 4 {
+			continue
+		}
+		for i := range cells {
+			cells[i] = strings.TrimSpace(cells[i])
+		}
+		if cells[0] == "Package" || strings.HasPrefix(cells[0], "-") {
+			continue
+		}
+
+		name, dev := cells[0], false
+		if strings.HasSuffix(name, " (dev)") {
+			name, dev = strings.TrimSuffix(name, " (dev)"), true
+		}
+		if i := strings.LastIndex(name, " ("); i > 0 {
+			name = name[:i]
+		}
+
+		outdated = append(outdated, Outdated{Name: name, Current: cells[1], Wanted: cells[2], Latest: cells[3], Dev: dev})
+	}
+
+	return outdated, nil
+}
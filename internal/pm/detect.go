@@ -0,0 +1,80 @@
+package pm
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Detection is the manager a web directory uses and how it was found.
+type Detection struct {
+	Manager string
+	// Source is "package.json", the lockfile name or "PATH".
+	Source string
+}
+
+// lockfileOrder is the precedence used when several lockfiles exist and
+// package.json does not say which manager it wants.
+var lockfileOrder = []PackageManager{&bun{}, &pnpm{}, &yarnClassic{}, &npm{}}
+
+// pathOrder is the PATH fallback for projects without any hint; npm ships
+// with Node.js, so it goes first.
+var pathOrder = []string{"npm", "pnpm", "yarn", "bun"}
+
+// DetectProject finds the manager of the web project in dir from the
+// packageManager field of package.json, then from lockfiles. It returns
+// false when neither gives a hint.
+func DetectProject(dir string) (Detection, bool) {
+	if name := packageManagerField(dir); name != "" {
+		return Detection{Manager: name, Source: "package.json"}, true
+	}
+
+	for _, m := range lockfileOrder {
+		for _, lockfile := range m.Lockfiles() {
+			if fileExists(filepath.Join(dir, lockfile)) {
+				return Detection{Manager: m.Name(), Source: lockfile}, true
+			}
+		}
+	}
+
+	return Detection{}, false
+}
+
+// Detect is DetectProject with a fallback to the first manager on PATH, or
+// npm when none is installed.
+func Detect(dir string) Detection {
+	if d, ok := DetectProject(dir); ok {
+		return d
+	}
+
+	for _, name := range pathOrder {
+		if _, err := exec.LookPath(name); err == nil {
+			return Detection{Manager: name, Source: "PATH"}
+		}
+	}
+	return Detection{Manager: "npm", Source: "PATH"}
+}
+
+// packageManagerField returns the manager named by package.json's
+// packageManager field (e.g. "pnpm@9.1.0+sha512..."), if supported.
+func packageManagerField(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return ""
+	}
+
+	var pkg struct {
+		PackageManager string `json:"packageManager"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(pkg.PackageManager, "@")
+	if !IsSupported(name) {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,139 @@
+package pm
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type npm struct {
+	dir string
+}
+
+func (m *npm) Name() string { return "npm" }
+
+func (m *npm) Lockfiles() []string { return []string{"package-lock.json", "npm-shrinkwrap.json"} }
+
+func (m *npm) Install() []string { return []string{"install"} }
+
+func (m *npm) FrozenInstall() []string { return []string{"ci"} }
+
+func (m *npm) Vendor(cacheDir string) []string {
+	return []string{"install", "--cache", cacheDir, "--prefer-offline"}
+}
+
+func (m *npm) OfflineInstall(cacheDir string, frozen bool) []string {
+	install := m.Install()
+	if frozen {
+		install = m.FrozenInstall()
+	}
+	return join(install, []string{"--offline"}, option("--cache", cacheDir))
+}
+
+func (m *npm) CacheEnv(cacheDir string) []string { return nil }
+
+func (m *npm) Add(packages []string, opts AddOptions) []string {
+	return join([]string{"install"}, flag(opts.Dev, "--save-dev"), flag(opts.Exact, "--save-exact"), packages)
+}
+
+func (m *npm) Remove(packages []string) []string {
+	return join([]string{"uninstall"}, packages)
+}
+
+func (m *npm) Run(script string, args ...string) []string {
+	if len(args) == 0 {
+		return []string{"run", script}
+	}
+	return join([]string{"run", script, "--"}, args)
+}
+
+func (m *npm) Exec(bin string, args ...string) []string {
+	return join([]string{"exec", "--", bin}, args)
+}
+
+func (m *npm) Upgrade(packages []string, latest bool) ([][]string, error) {
+	if !latest {
+		return [][]string{join([]string{"update"}, packages)}, nil
+	}
+
+	// npm update never crosses the declared range, so bump to @latest with
+	// install, keeping each package in its dependency section.
+	prod, dev, err := m.splitByDependencyType(packages)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs [][]string
+	if len(prod) > 0 {
+		runs = append(runs, join([]string{"install"}, withLatestTag(prod)))
+	}
+	if len(dev) > 0 {
+		runs = append(runs, join([]string{"install", "--save-dev"}, withLatestTag(dev)))
+	}
+	return runs, nil
+}
+
+func (m *npm) Outdated() ([]string, error) {
+	return []string{"outdated", "--json", "--long"}, nil
+}
+
+func (m *npm) ParseOutdated(out string) ([]Outdated, error) {
+	return parseOutdatedJSON(out)
+}
+
+// splitByDependencyType sorts names (all declared packages when empty) into
+// dependencies and devDependencies.
+func (m *npm) splitByDependencyType(names []string) (prod, dev []string, err error) {
+	data, err := os.ReadFile(filepath.Join(m.dir, "package.json"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, nil, err
+	}
+
+	if len(names) == 0 {
+		for name := range pkg.Dependencies {
+			names = append(names, name)
+		}
+		for name := range pkg.DevDependencies {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	for _, name := range names {
+		if _, ok := pkg.DevDependencies[packageName(name)]; ok {
+			dev = append(dev, name)
+		} else {
+			prod = append(prod, name)
+		}
+	}
+	return prod, dev, nil
+}
+
+// withLatestTag replaces the version or tag of each spec with @latest, so
+// "react@18" becomes "react@latest" instead of the invalid "react@18@latest".
+func withLatestTag(specs []string) []string {
+	tagged := make([]string, len(specs))
+	for i, spec := range specs {
+		tagged[i] = packageName(spec) + "@latest"
+	}
+	return tagged
+}
+
+// packageName strips the version or tag from a spec such as "react@18" or
+// "@types/node@^20", keeping the scope of scoped packages.
+func packageName(spec string) string {
+	if i := strings.LastIndexByte(spec, '@'); i > 0 {
+		return spec[:i]
+	}
+	return spec
+}
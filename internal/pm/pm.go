@@ -0,0 +1,156 @@
+// Package pm builds the command lines for the supported JavaScript package
+// managers. Each manager is one type implementing PackageManager; callers
+// run the returned arguments with the manager's binary in the web directory.
+package pm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type PackageManager interface {
+	// Name is the manager's binary.
+	Name() string
+	// Lockfiles are the lockfile names the manager writes.
+	Lockfiles() []string
+
+	Install() []string
+	// FrozenInstall installs exactly what the lockfile says and fails when
+	// it is missing or out of date.
+	FrozenInstall() []string
+	// Vendor fills cacheDir with the packages the lockfile needs so that
+	// OfflineInstall can run without network access later.
+	Vendor(cacheDir string) []string
+	// OfflineInstall installs from cacheDir, or the manager's own cache when
+	// it is empty, without contacting the registry.
+	OfflineInstall(cacheDir string, frozen bool) []string
+	// CacheEnv returns the environment variables that point Vendor and
+	// OfflineInstall at cacheDir, for managers without an option for it.
+	CacheEnv(cacheDir string) []string
+
+	Add(packages []string, opts AddOptions) []string
+	Remove(packages []string) []string
+
+	// Run runs a package.json script, forwarding args to it.
+	Run(script string, args ...string) []string
+	// Exec runs a binary from node_modules/.bin.
+	Exec(bin string, args ...string) []string
+
+	// Upgrade returns the commands that upgrade packages (all when empty),
+	// to the latest versions instead of within their ranges if latest is set.
+	Upgrade(packages []string, latest bool) ([][]string, error)
+	// Outdated returns the command listing outdated packages and ParseOutdated
+	// reads its output.
+	Outdated() ([]string, error)
+	ParseOutdated(out string) ([]Outdated, error)
+}
+
+type AddOptions struct {
+	Dev   bool
+	Exact bool
+}
+
+type Outdated struct {
+	Name    string
+	Current string
+	Wanted  string
+	Latest  string
+	Dev     bool
+}
+
+// Names lists the supported managers.
+var Names = []string{"npm", "yarn", "pnpm", "bun"}
+
+func IsSupported(name string) bool {
+	for _, n := range Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// New returns the manager called name for the project in dir.
+func New(name, dir string) (PackageManager, error) {
+	switch name {
+	case "npm":
+		return &npm{dir: dir}, nil
+	case "yarn":
+		if isYarnBerry(dir) {
+			return &yarnBerry{}, nil
+		}
+		return &yarnClassic{}, nil
+	case "pnpm":
+		return &pnpm{}, nil
+	case "bun":
+		return &bun{}, nil
+	}
+	return nil, fmt.Errorf("unsupported package manager: %s", name)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func join(parts ...[]string) []string {
+	var out []string
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+func flag(set bool, name string) []string {
+	if set {
+		return []string{name}
+	}
+	return nil
+}
+
+func option(name, value string) []string {
+	if value != "" {
+		return []string{name, value}
+	}
+	return nil
+}
+
+// parseOutdatedJSON reads `npm outdated --json --long` and
+// `pnpm outdated --format json`, which share the same shape.
+func parseOutdatedJSON(out string) ([]Outdated, error) {
+	if out == "" {
+		return nil, nil
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(out), &raw); err != nil {
+		return nil, fmt.Errorf("unexpected outdated output: %v", err)
+	}
+
+	var outdated []Outdated
+	for name, msg := range raw {
+		var entry struct {
+			Current        string `json:"current"`
+			Wanted         string `json:"wanted"`
+			Latest         string `json:"latest"`
+			Type           string `json:"type"`
+			DependencyType string `json:"dependencyType"`
+		}
+		// npm reports an array when a package is outdated in several
+		// workspaces; those are skipped.
+		if err := json.Unmarshal(msg, &entry); err != nil {
+			continue
+		}
+
+		outdated = append(outdated, Outdated{
+			Name:    name,
+			Current: entry.Current,
+			Wanted:  entry.Wanted,
+			Latest:  entry.Latest,
+			Dev:     entry.Type == "devDependencies" || entry.DependencyType == "devDependencies",
+		})
+	}
+
+	return outdated, nil
+}
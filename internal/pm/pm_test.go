@@ -0,0 +1,199 @@
+package pm
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestParseOutdated(t *testing.T) {
+	tests := []struct {
+		name    string
+		manager PackageManager
+		out     string
+		want    []Outdated
+	}{
+		{
+			name:    "npm JSON",
+			manager: &npm{},
+			out: `{
+  "react": {"current": "18.2.0", "wanted": "18.3.1", "latest": "19.0.0", "dependent": "web", "location": "node_modules/react", "type": "dependencies"},
+  "vite": {"current": "5.0.0", "wanted": "5.4.11", "latest": "6.0.3", "dependent": "web", "location": "node_modules/vite", "type": "devDependencies"}
+}`,
+			want: []Outdated{
+				{Name: "react", Current: "18.2.0", Wanted: "18.3.1", Latest: "19.0.0"},
+				{Name: "vite", Current: "5.0.0", Wanted: "5.4.11", Latest: "6.0.3", Dev: true},
+			},
+		},
+		{
+			name:    "npm JSON skips packages outdated in several workspaces",
+			manager: &npm{},
+			out: `{
+  "react": [{"current": "18.2.0", "wanted": "18.3.1", "latest": "19.0.0", "dependent": "a"}, {"current": "18.1.0", "wanted": "18.3.1", "latest": "19.0.0", "dependent": "b"}],
+  "vite": {"current": "5.0.0", "wanted": "5.4.11", "latest": "6.0.3", "type": "devDependencies"}
+}`,
+			want: []Outdated{
+				{Name: "vite", Current: "5.0.0", Wanted: "5.4.11", Latest: "6.0.3", Dev: true},
+			},
+		},
+		{
+			name:    "npm with nothing outdated",
+			manager: &npm{},
+			out:     "",
+			want:    nil,
+		},
+		{
+			name:    "pnpm JSON",
+			manager: &pnpm{},
+			out: `{
+  "react": {"current": "18.2.0", "latest": "19.0.0", "wanted": "18.3.1", "isDeprecated": false, "dependencyType": "dependencies"},
+  "typescript": {"current": "5.4.5", "latest": "5.7.2", "wanted": "5.4.5", "isDeprecated": false, "dependencyType": "devDependencies"}
+}`,
+			want: []Outdated{
+				{Name: "react", Current: "18.2.0", Wanted: "18.3.1", Latest: "19.0.0"},
+				{Name: "typescript", Current: "5.4.5", Wanted: "5.4.5", Latest: "5.7.2", Dev: true},
+			},
+		},
+		{
+			name:    "pnpm with nothing outdated",
+			manager: &pnpm{},
+			out:     "{}",
+			want:    nil,
+		},
+		{
+			name:    "yarn classic NDJSON",
+			manager: &yarnClassic{},
+			out: `{"type":"info","data":"Color legend : \n \"<red>\"    : Major Update backward-incompatible updates"}
+{"type":"table","data":{"head":["Package","Current","Wanted","Latest","Package Type","URL"],"body":[["react","18.2.0","18.3.1","19.0.0","dependencies","https://react.dev/"],["vite","5.0.0","5.4.11","6.0.3","devDependencies","https://vite.dev"]]}}
+`,
+			want: []Outdated{
+				{Name: "react", Current: "18.2.0", Wanted: "18.3.1", Latest: "19.0.0"},
+				{Name: "vite", Current: "5.0.0", Wanted: "5.4.11", Latest: "6.0.3", Dev: true},
+			},
+		},
+		{
+			name:    "yarn classic skips short rows and other messages",
+			manager: &yarnClassic{},
+			out: `{"type":"warning","data":"package.json: No license field"}
+not json
+{"type":"table","data":{"head":[],"body":[["broken","1.0.0"],["react","18.2.0","18.3.1","19.0.0","dependencies","https://react.dev/"]]}}
+`,
+			want: []Outdated{
+				{Name: "react", Current: "18.2.0", Wanted: "18.3.1", Latest: "19.0.0"},
+			},
+		},
+		{
+			name:    "bun box-drawing table",
+			manager: &bun{},
+			out: "bun outdated v1.1.38 (bf2f153f)\n" +
+				"┌──────────────────┬─────────┬────────┬────────┐\n" +
+				"│ Package          │ Current │ Update │ Latest │\n" +
+				"├──────────────────┼─────────┼────────┼────────┤\n" +
+				"│ react            │ 18.2.0  │ 18.3.1 │ 19.0.0 │\n" +
+				"├──────────────────┼─────────┼────────┼────────┤\n" +
+				"│ @types/bun (dev) │ 1.1.0   │ 1.1.8  │ 1.1.8  │\n" +
+				"├──────────────────┼─────────┼────────┼────────┤\n" +
+				"│ fsevents (optional) │ 2.3.2 │ 2.3.3  │ 2.3.3  │\n" +
+				"└──────────────────┴─────────┴────────┴────────┘\n",
+			want: []Outdated{
+				{Name: "@types/bun", Current: "1.1.0", Wanted: "1.1.8", Latest: "1.1.8", Dev: true},
+				{Name: "fsevents", Current: "2.3.2", Wanted: "2.3.3", Latest: "2.3.3"},
+				{Name: "react", Current: "18.2.0", Wanted: "18.3.1", Latest: "19.0.0"},
+			},
+		},
+		{
+			name:    "bun ASCII table with colors",
+			manager: &bun{},
+			out: "|----------------------------------------|\n" +
+				"| Package        | Current | Update | Latest |\n" +
+				"|----------------|---------|--------|--------|\n" +
+				"| \x1b[1mvite\x1b[0m (dev) | 5.0.0   | \x1b[32m5.4.11\x1b[0m | \x1b[31m6.0.3\x1b[0m  |\n" +
+				"|----------------------------------------|\n",
+			want: []Outdated{
+				{Name: "vite", Current: "5.0.0", Wanted: "5.4.11", Latest: "6.0.3", Dev: true},
+			},
+		},
+		{
+			name:    "bun with nothing outdated",
+			manager: &bun{},
+			out:     "bun outdated v1.1.38 (bf2f153f)\n",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.manager.ParseOutdated(tt.out)
+			if err != nil {
+				t.Fatalf("ParseOutdated: %v", err)
+			}
+			// The JSON parsers return map order; callers sort.
+			sort.Slice(got, func(i, j int) bool { return got[i].Name < got[j].Name })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseOutdated =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOutdatedJSONErrors(t *testing.T) {
+	if _, err := parseOutdatedJSON("npm ERR! code ENOLOCK"); err == nil {
+		t.Fatal("parseOutdatedJSON accepted output that is not JSON")
+	}
+}
+
+func TestNpmUpgradeLatest(t *testing.T) {
+	dir := t.TempDir()
+	pkg := `{
+  "dependencies": {"react": "^18.2.0", "@scope/ui": "^1.0.0"},
+  "devDependencies": {"@types/node": "^20.0.0", "vite": "^5.0.0"}
+}`
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(pkg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := &npm{dir: dir}
+
+	tests := []struct {
+		name     string
+		packages []string
+		want     [][]string
+	}{
+		{
+			name: "all packages",
+			want: [][]string{
+				{"install", "@scope/ui@latest", "react@latest"},
+				{"install", "--save-dev", "@types/node@latest", "vite@latest"},
+			},
+		},
+		{
+			name:     "plain and scoped names",
+			packages: []string{"react", "@types/node"},
+			want: [][]string{
+				{"install", "react@latest"},
+				{"install", "--save-dev", "@types/node@latest"},
+			},
+		},
+		{
+			name:     "versioned and tagged specs",
+			packages: []string{"react@18", "@scope/ui@^1.2", "@types/node@20.1.0", "vite@next"},
+			want: [][]string{
+				{"install", "react@latest", "@scope/ui@latest"},
+				{"install", "--save-dev", "@types/node@latest", "vite@latest"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.Upgrade(tt.packages, true)
+			if err != nil {
+				t.Fatalf("Upgrade: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Upgrade = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package pm
+
+type pnpm struct{}
+
+func (m *pnpm) Name() string { return "pnpm" }
+
+func (m *pnpm) Lockfiles() []string { return []string{"pnpm-lock.yaml"} }
+
+func (m *pnpm) Install() []string { return []string{"install"} }
+
+func (m *pnpm) FrozenInstall() []string { return []string{"install", "--frozen-lockfile"} }
+
+// Vendor uses pnpm fetch, which only needs the lockfile.
+func (m *pnpm) Vendor(cacheDir string) []string {
+	return []string{"fetch", "--store-dir", cacheDir}
+}
+
+func (m *pnpm) OfflineInstall(cacheDir string, frozen bool) []string {
+	return join([]string{"install", "--offline"}, flag(frozen, "--frozen-lockfile"), option("--store-dir", cacheDir))
+}
+
+func (m *pnpm) CacheEnv(cacheDir string) []string { return nil }
+
+func (m *pnpm) Add(packages []string, opts AddOptions) []string {
+	return join([]string{"add"}, flag(opts.Dev, "--save-dev"), flag(opts.Exact, "--save-exact"), packages)
+}
+
+func (m *pnpm) Remove(packages []string) []string {
+	return join([]string{"remove"}, packages)
+}
+
+// Run passes args straight through: pnpm forwards a "--" to the script
+// literally.
+func (m *pnpm) Run(script string, args ...string) []string {
+	return join([]string{"run", script}, args)
+}
+
+func (m *pnpm) Exec(bin string, args ...string) []string {
+	return join([]string{"exec", bin}, args)
+}
+
+func (m *pnpm) Upgrade(packages []string, latest bool) ([][]string, error) {
+	return [][]string{join([]string{"update"}, packages, flag(latest, "--latest"))}, nil
+}
+
+func (m *pnpm) Outdated() ([]string, error) {
+	return []string{"outdated", "--format", "json"}, nil
+}
+
+func (m *pnpm) ParseOutdated(out string) ([]Outdated, error) {
+	return parseOutdatedJSON(out)
+}
@@ -0,0 +1,180 @@
+package pm
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// yarnClassic is yarn 1.x.
+type yarnClassic struct{}
+
+func (m *yarnClassic) Name() string { return "yarn" }
+
+func (m *yarnClassic) Lockfiles() []string { return []string{"yarn.lock"} }
+
+func (m *yarnClassic) Install() []string { return []string{"install"} }
+
+func (m *yarnClassic) FrozenInstall() []string { return []string{"install", "--frozen-lockfile"} }
+
+func (m *yarnClassic) Vendor(cacheDir string) []string {
+	return []string{"install", "--cache-folder", cacheDir}
+}
+
+func (m *yarnClassic) OfflineInstall(cacheDir string, frozen bool) []string {
+	return join([]string{"install", "--offline"}, flag(frozen, "--frozen-lockfile"), option("--cache-folder", cacheDir))
+}
+
+func (m *yarnClassic) CacheEnv(cacheDir string) []string { return nil }
+
+func (m *yarnClassic) Add(packages []string, opts AddOptions) []string {
+	return join([]string{"add"}, flag(opts.Dev, "--dev"), flag(opts.Exact, "--exact"), packages)
+}
+
+func (m *yarnClassic) Remove(packages []string) []string {
+	return join([]string{"remove"}, packages)
+}
+
+func (m *yarnClassic) Run(script string, args ...string) []string {
+	return join([]string{"run", script}, args)
+}
+
+// Exec uses run, which also resolves binaries; yarn 1 has no exec command.
+func (m *yarnClassic) Exec(bin string, args ...string) []string {
+	return join([]string{"run", bin}, args)
+}
+
+func (m *yarnClassic) Upgrade(packages []string, latest bool) ([][]string, error) {
+	return [][]string{join([]string{"upgrade"}, packages, flag(latest, "--latest"))}, nil
+}
+
+func (m *yarnClassic) Outdated() ([]string, error) {
+	return []string{"outdated", "--json"}, nil
+}
+
+// ParseOutdated reads the NDJSON of `yarn outdated --json`.
+func (m *yarnClassic) ParseOutdated(out string) ([]Outdated, error) {
+	var outdated []Outdated
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var msg struct {
+			Type string `json:"type"`
+			Data struct {
+				Body [][]string `json:"body"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil || msg.Type != "table" {
+			continue
+		}
+
+		// Columns: Package, Current, Wanted, Latest, Package Type, URL.
+		for _, row := range msg.Data.Body {
+			if len(row) < 5 {
+				continue
+			}
+			outdated = append(outdated, Outdated{Name: row[0], Current: row[1], Wanted: row[2], Latest: row[3], Dev: row[4] == "devDependencies"})
+		}
+	}
+
+	return outdated, scanner.Err()
+}
+
+// yarnBerry is yarn 2 and newer.
+type yarnBerry struct{}
+
+func (m *yarnBerry) Name() string { return "yarn" }
+
+func (m *yarnBerry) Lockfiles() []string { return []string{"yarn.lock"} }
+
+func (m *yarnBerry) Install() []string { return []string{"install"} }
+
+func (m *yarnBerry) FrozenInstall() []string { return []string{"install", "--immutable"} }
+
+// Vendor is a plain install: CacheEnv points it at cacheDir.
+func (m *yarnBerry) Vendor(cacheDir string) []string { return m.Install() }
+
+// OfflineInstall uses --immutable-cache, which fails instead of downloading
+// when a package is missing from the cache.
+func (m *yarnBerry) OfflineInstall(cacheDir string, frozen bool) []string {
+	return join([]string{"install", "--immutable-cache"}, flag(frozen, "--immutable"))
+}
+
+// CacheEnv sets the cache folder through the environment, Yarn 2+ has no
+// command line option for it. The global cache, on by default since Yarn 4,
+// would ignore the folder.
+func (m *yarnBerry) CacheEnv(cacheDir string) []string {
+	if cacheDir == "" {
+		return nil
+	}
+	return []string{"YARN_ENABLE_GLOBAL_CACHE=false", "YARN_CACHE_FOLDER=" + cacheDir}
+}
+
+func (m *yarnBerry) Add(packages []string, opts AddOptions) []string {
+	return join([]string{"add"}, flag(opts.Dev, "--dev"), flag(opts.Exact, "--exact"), packages)
+}
+
+func (m *yarnBerry) Remove(packages []string) []string {
+	return join([]string{"remove"}, packages)
+}
+
+func (m *yarnBerry) Run(script string, args ...string) []string {
+	return join([]string{"run", script}, args)
+}
+
+func (m *yarnBerry) Exec(bin string, args ...string) []string {
+	return join([]string{"exec", bin}, args)
+}
+
+// Upgrade uses yarn up, which moves the range to the latest version, or
+// yarn up -R, which refreshes named packages within their ranges. There is
+// no in-range form for every package.
+func (m *yarnBerry) Upgrade(packages []string, latest bool) ([][]string, error) {
+	if latest {
+		if len(packages) == 0 {
+			packages = []string{"*"}
+		}
+		return [][]string{join([]string{"up"}, packages)}, nil
+	}
+	if len(packages) == 0 {
+		return nil, fmt.Errorf("yarn 2+ can only upgrade named packages within their ranges; name them or pass --latest")
+	}
+	return [][]string{join([]string{"up", "-R"}, packages)}, nil
+}
+
+func (m *yarnBerry) Outdated() ([]string, error) {
+	return nil, fmt.Errorf("yarn 2+ has no outdated command; use yarn upgrade-interactive")
+}
+
+func (m *yarnBerry) ParseOutdated(out string) ([]Outdated, error) {
+	return nil, nil
+}
+
+// isYarnBerry reports whether dir uses yarn 2 or newer, going by
+// .yarnrc.yml, the packageManager field and finally `yarn --version`.
+func isYarnBerry(dir string) bool {
+	if fileExists(filepath.Join(dir, ".yarnrc.yml")) {
+		return true
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		var pkg struct {
+			PackageManager string `json:"packageManager"`
+		}
+		if json.Unmarshal(data, &pkg) == nil {
+			if version, ok := strings.CutPrefix(pkg.PackageManager, "yarn@"); ok {
+				return !strings.HasPrefix(version, "1.")
+			}
+		}
+	}
+
+	cmd := exec.Command("yarn", "--version")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	return err == nil && !strings.HasPrefix(strings.TrimSpace(string(out)), "1.")
+}
@@ -0,0 +1,231 @@
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+const ManifestFile = "template.json"
+
+type Manifest struct {
+	Name      string     `json:"name"`
+	Variables []Variable `json:"variables"`
+	Hooks     Hooks      `json:"hooks"`
+}
+
+type Variable struct {
+	Name    string `json:"name"`
+	Prompt  string `json:"prompt"`
+	Default string `json:"default"`
+}
+
+type Hooks struct {
+	PostCreate []string `json:"postCreate"`
+}
+
+type Source struct {
+	Name     string
+	Manifest Manifest
+	// Remote is set for templates cloned from a git URL, whose hooks the
+	// user has not reviewed.
+	Remote  bool
+	files   fs.FS
+	cleanup func()
+}
+
+var defaultSource = func() *Source {
+	files, _ := fs.Sub(templateFiles, "files")
+	return &Source{
+		Name:  "default",
+		files: files,
+	}
+}()
+
+func Default() *Source {
+	return defaultSource
+}
+
+// Resolve loads a template from a local directory or clones it from a git
+// repository. A ref can be selected with a "#ref" suffix on the URL.
+func Resolve(ctx context.Context, from string) (*Source, error) {
+	if info, err := os.Stat(from); err == nil {
+		if !info.IsDir() {
+			return nil, fmt.Errorf("template path is not a directory: %s", from)
+		}
+		return loadSource(from, os.DirFS(from), nil)
+	}
+
+	if !isGitURL(from) {
+		return nil, fmt.Errorf("template not found: %s", from)
+	}
+
+	return cloneSource(ctx, from)
+}
+
+func isGitURL(value string) bool {
+	return strings.Contains(value, "://") ||
+		strings.HasPrefix(value, "git@") ||
+		strings.HasSuffix(strings.SplitN(value, "#", 2)[0], ".git")
+}
+
+func cloneSource(ctx context.Context, from string) (*Source, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git is required to use remote templates")
+	}
+
+	url, ref, _ := strings.Cut(from, "#")
+
+	tmpDir, err := os.MkdirTemp("", "eel-template-")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() { os.RemoveAll(tmpDir) }
+
+	args := []string{"clone", "--depth", "1"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	args = append(args, url, tmpDir)
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		cleanup()
+		return nil, fmt.Errorf("failed to clone template %s: %v", from, err)
+	}
+
+	source, err := loadSource(from, os.DirFS(tmpDir), cleanup)
+	if err != nil {
+		return nil, err
+	}
+	source.Remote = true
+	return source, nil
+}
+
+func loadSource(name string, files fs.FS, cleanup func()) (*Source, error) {
+	source := &Source{
+		Name:    name,
+		files:   files,
+		cleanup: cleanup,
+	}
+
+	data, err := fs.ReadFile(files, ManifestFile)
+	if err != nil && !os.IsNotExist(err) {
+		source.Close()
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &source.Manifest); err != nil {
+			source.Close()
+			return nil, fmt.Errorf("invalid %s: %v", ManifestFile, err)
+		}
+	}
+
+	return source, nil
+}
+
+func (s *Source) Close() {
+	if s.cleanup != nil {
+		s.cleanup()
+		s.cleanup = nil
+	}
+}
+
+// HasFile reports whether the template ships the given slash-separated path.
+func (s *Source) HasFile(path string) bool {
+	if _, err := fs.Stat(s.files, path); err == nil {
+		return true
+	}
+	_, err := fs.Stat(s.files, path+templateSuffix)
+	return err == nil
+}
+
+func (s *Source) Copy(projectDir string, data Data) error {
+	return fs.WalkDir(s.files, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == "." || path == ManifestFile {
+			return nil
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return fs.SkipDir
+		}
+
+		targetPath := filepath.Join(projectDir, filepath.FromSlash(strings.TrimSuffix(path, templateSuffix)))
+
+		if d.IsDir() {
+			return os.MkdirAll(targetPath, 0755)
+		}
+
+		content, err := fs.ReadFile(s.files, path)
+		if err != nil {
+			return err
+		}
+
+		if strings.HasSuffix(path, templateSuffix) {
+			content, err = render(path, content, data)
+			if err != nil {
+				return err
+			}
+		}
+
+		perm := os.FileMode(0644)
+		if info, err := d.Info(); err == nil && info.Mode()&0111 != 0 {
+			perm = 0755
+		}
+
+		return os.WriteFile(targetPath, content, perm)
+	})
+}
+
+// RenderHook renders a post-create hook. Substituted values are shell-quoted,
+// so a project name or variable can't inject commands.
+func (s *Source) RenderHook(hook string, data Data) (string, error) {
+	out, err := render("hook", []byte(hook), data.shellQuoted())
+	return string(out), err
+}
+
+func (d Data) shellQuoted() Data {
+	q := d
+	q.ProjectName = ShellQuote(d.ProjectName)
+	q.PackageName = ShellQuote(d.PackageName)
+	q.Author = ShellQuote(d.Author)
+	q.AuthorEmail = ShellQuote(d.AuthorEmail)
+	q.PythonVersion = ShellQuote(d.PythonVersion)
+	q.Manager = ShellQuote(d.Manager)
+
+	q.Dependencies = make([]string, len(d.Dependencies))
+	for i, dep := range d.Dependencies {
+		q.Dependencies[i] = ShellQuote(dep)
+	}
+	q.Vars = make(map[string]string, len(d.Vars))
+	for k, v := range d.Vars {
+		q.Vars[k] = ShellQuote(v)
+	}
+
+	return q
+}
+
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_./:=@%+,-]+$`)
+
+// ShellQuote quotes value as one word for the shell hooks run in: sh, or
+// cmd.exe on Windows. Plain words are left as they are.
+func ShellQuote(value string) string {
+	if shellSafe.MatchString(value) {
+		return value
+	}
+	if runtime.GOOS == "windows" {
+		return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -1,38 +1,147 @@
 package template
 
 import (
+	"bytes"
 	"embed"
-	"io/fs"
-	"os"
+	"fmt"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"text/template"
 )
 
 //go:embed files/*
 var templateFiles embed.FS
 
-func CopyTemplateFiles(projectDir string) error {
-	return fs.WalkDir(templateFiles, "files", func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
+const templateSuffix = ".tmpl"
 
-		if path == "files" {
-			return nil
-		}
+type Data struct {
+	ProjectName   string
+	PackageName   string
+	Author        string
+	AuthorEmail   string
+	PythonVersion string
+	Manager       string
+	WindowWidth   int
+	WindowHeight  int
+	Dependencies  []string
+	Vars          map[string]string
+}
 
-		relPath := strings.TrimPrefix(path, "files/")
-		targetPath := filepath.Join(projectDir, relPath)
+var packageNameSeparators = regexp.MustCompile(`[^a-z0-9]+`)
 
-		if d.IsDir() {
-			return os.MkdirAll(targetPath, 0755)
-		}
+func NewData(projectName, manager string) Data {
+	return Data{
+		ProjectName:   projectName,
+		PackageName:   NormalizePackageName(projectName),
+		PythonVersion: "3.10",
+		Manager:       manager,
+		WindowWidth:   1000,
+		WindowHeight:  700,
+		Dependencies:  []string{"Eel>=0.16.0"},
+		Vars:          map[string]string{},
+	}
+}
+
+// NormalizePackageName turns a directory name into a PEP 503 style
+// distribution name ("My App" -> "my-app").
+func NormalizePackageName(name string) string {
+	normalized := packageNameSeparators.ReplaceAllString(strings.ToLower(filepath.Base(name)), "-")
+	normalized = strings.Trim(normalized, "-")
+	if normalized == "" {
+		return "eel-app"
+	}
+	return normalized
+}
+
+var pythonVersionPattern = regexp.MustCompile(`^(?:python|cpython-?)?(\d+)\.(\d+)(?:\.\d+)?(?:(?:a|b|rc)\d+|\.dev\d+)?t?$`)
+
+// minPythonMinor is the oldest Python 3 the generated main.py runs on; it
+// uses `str | None` annotations.
+const minPythonMinor = 10
 
-		content, err := templateFiles.ReadFile(path)
-		if err != nil {
-			return err
+// ParsePythonVersion cuts a version such as "3.12.4" (as pyenv writes to
+// .python-version) to the "3.12" that ruff's and mypy's settings accept.
+func ParsePythonVersion(value string) (string, error) {
+	m := pythonVersionPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(value)))
+	if m == nil {
+		return "", fmt.Errorf("invalid Python version: %s. Expected MAJOR.MINOR, e.g. 3.12", value)
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	if major < 3 || major == 3 && minor < minPythonMinor {
+		return "", fmt.Errorf("unsupported Python version: %s. eel-cli projects need Python 3.%d or newer", value, minPythonMinor)
+	}
+	return m[1] + "." + m[2], nil
+}
+
+// PythonTag returns the ruff/pyinstaller style target tag, e.g. "py310".
+func (d Data) PythonTag() string {
+	return "py" + strings.ReplaceAll(d.PythonVersion, ".", "")
+}
+
+var funcs = template.FuncMap{
+	"quote": tomlQuote,
+}
+
+// tomlQuote writes s as a TOML basic string. strconv.Quote would do for
+// most values, but its \x00, \a and \v escapes are not valid TOML.
+func tomlQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
 		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func CopyTemplateFiles(projectDir string, data Data) error {
+	return Default().Copy(projectDir, data)
+}
+
+// RenderFile renders a single built-in template file, e.g. "pyproject.toml".
+func RenderFile(name string, data Data) ([]byte, error) {
+	content, err := templateFiles.ReadFile("files/" + name + templateSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	return render(name, content, data)
+}
+
+func render(name string, content []byte, data any) ([]byte, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
 
-		return os.WriteFile(targetPath, content, 0644)
-	})
+	return buf.Bytes(), nil
 }
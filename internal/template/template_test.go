@@ -0,0 +1,81 @@
+package template
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTomlQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"my-app", `"my-app"`},
+		{"", `""`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`C:\Users\me`, `"C:\\Users\\me"`},
+		{"a\bb\tc\nd\fe\rf", `"a\bb\tc\nd\fe\rf"`},
+		{"nul\x00bell\avt\vesc\x1bdel\x7f", `"nul\u0000bell\u0007vt\u000Besc\u001Bdel\u007F"`},
+		{"Zoë 日本", `"Zoë 日本"`},
+		{"bad\xffbyte", "\"bad\uFFFDbyte\""},
+	}
+
+	for _, tt := range tests {
+		if got := tomlQuote(tt.in); got != tt.want {
+			t.Errorf("tomlQuote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRenderPyprojectQuotesValues(t *testing.T) {
+	data := NewData("app", "npm")
+	data.ProjectName = "My \"App\"\x00"
+	data.Author = "Jo \\ \a"
+
+	out, err := RenderFile("pyproject.toml", data)
+	if err != nil {
+		t.Fatalf("RenderFile: %v", err)
+	}
+
+	for _, want := range []string{
+		`description = "My \"App\"\u0000 - Eel desktop application"`,
+		`name = "Jo \\ \u0007"`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("pyproject.toml does not contain %s:\n%s", want, out)
+		}
+	}
+}
+
+func TestParsePythonVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr string
+	}{
+		{in: "3.12", want: "3.12"},
+		{in: "3.12.4", want: "3.12"},
+		{in: " 3.13t\n", want: "3.13"},
+		{in: "3.14.0rc1", want: "3.14"},
+		{in: "cpython-3.11.9", want: "3.11"},
+		{in: "3.10", want: "3.10"},
+		{in: "3.9", wantErr: "unsupported Python version: 3.9. eel-cli projects need Python 3.10 or newer"},
+		{in: "2.7.18", wantErr: "unsupported Python version: 2.7.18"},
+		{in: "3", wantErr: "invalid Python version: 3"},
+		{in: "pypy3.10", wantErr: "invalid Python version: pypy3.10"},
+		{in: "latest", wantErr: "invalid Python version: latest"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePythonVersion(tt.in)
+		if tt.wantErr != "" {
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("ParsePythonVersion(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParsePythonVersion(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
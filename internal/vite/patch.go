@@ -0,0 +1,474 @@
+package vite
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var configNames = []string{
+	"vite.config.ts",
+	"vite.config.js",
+	"vite.config.mts",
+	"vite.config.mjs",
+	"vite.config.cts",
+	"vite.config.cjs",
+}
+
+// ErrConfigNotFound is returned by FindConfig when the web directory has no
+// vite config, as in create-vite's vanilla templates.
+var ErrConfigNotFound = errors.New("vite config not found")
+
+// DefaultConfig is the config written when a project has none; Patch adds
+// the options to it.
+const DefaultConfig = `import { defineConfig } from 'vite'
+
+export default defineConfig({})
+`
+
+type Options struct {
+	OutDir      string
+	EmptyOutDir bool
+	Base        string
+	Plugins     []Plugin
+}
+
+func FindConfig(webDir string) (string, error) {
+	for _, name := range configNames {
+		path := filepath.Join(webDir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w in %s", ErrConfigNotFound, webDir)
+}
+
+// PatchFile rewrites the vite config at path and returns a unified diff of
+// the change. Nothing is written when the config is already up to date or
+// when the rewritten source does not parse.
+func PatchFile(path string, opts Options) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	original := string(data)
+	updated, err := Patch(original, opts)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", filepath.Base(path), err)
+	}
+
+	if updated == original {
+		return "", nil
+	}
+
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return "", err
+	}
+
+	return Diff(filepath.Base(path), original, updated), nil
+}
+
+func Patch(src string, opts Options) (string, error) {
+	if _, err := parse(src); err != nil {
+		return "", fmt.Errorf("cannot parse config: %v", err)
+	}
+
+	quote := detectQuote(src)
+
+	type edit struct {
+		path  []string
+		value string
+	}
+	edits := []edit{
+		{[]string{"build", "outDir"}, quote(opts.OutDir)},
+		{[]string{"build", "emptyOutDir"}, strconv.FormatBool(opts.EmptyOutDir)},
+	}
+	if opts.Base != "" {
+		edits = append(edits, edit{[]string{"base"}, quote(opts.Base)})
+	}
+
+	// New properties are inserted at the top of their object, so applying
+	// the edits in reverse keeps them in declaration order.
+	result := src
+	for i := len(edits) - 1; i >= 0; i-- {
+		var err error
+		result, err = setPath(result, edits[i].path, edits[i].value)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	for _, plugin := range opts.Plugins {
+		var err error
+		result, err = addPlugin(result, plugin)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := parse(result); err != nil {
+		return "", fmt.Errorf("refusing to write config that does not parse: %v", err)
+	}
+
+	return result, nil
+}
+
+// parse tokenizes src and locates the exported config object literal.
+func parse(src string) (int, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return -1, err
+	}
+	return findConfigObject(tokens)
+}
+
+func setPath(src string, path []string, value string) (string, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return "", err
+	}
+
+	obj, err := findConfigObject(tokens)
+	if err != nil {
+		return "", err
+	}
+
+	for depth := range path {
+		prop, ok, err := findProperty(tokens, obj, strings.Join(path[:depth+1], "."))
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return insertProperty(src, tokens, obj, path[depth:], value), nil
+		}
+
+		if depth == len(path)-1 {
+			current := src[tokens[prop.valueStart].start:tokens[prop.valueEnd].end]
+			if sameValue(current, value) {
+				return src, nil
+			}
+			return src[:tokens[prop.valueStart].start] + value + src[tokens[prop.valueEnd].end:], nil
+		}
+
+		open := tokens[prop.valueStart]
+		if open.text != "{" || open.match != prop.valueEnd {
+			return "", fmt.Errorf("%s is not an object literal", strings.Join(path[:depth+1], "."))
+		}
+		obj = prop.valueStart
+	}
+
+	return src, nil
+}
+
+func findConfigObject(tokens []token) (int, error) {
+	for i, tok := range tokens {
+		if tok.kind == tokIdent && tok.text == "defineConfig" {
+			open := nextSignificant(tokens, i)
+			if open >= 0 && tokens[open].text == "(" {
+				return resolveObject(tokens, nextSignificant(tokens, open))
+			}
+		}
+	}
+
+	for i, tok := range tokens {
+		if tok.kind == tokIdent && tok.text == "export" {
+			next := nextSignificant(tokens, i)
+			if next >= 0 && tokens[next].text == "default" {
+				return resolveObject(tokens, nextSignificant(tokens, next))
+			}
+		}
+		if tok.kind == tokIdent && tok.text == "module" {
+			dot := nextSignificant(tokens, i)
+			name := nextSignificant(tokens, dot)
+			eq := nextSignificant(tokens, name)
+			if dot >= 0 && name >= 0 && eq >= 0 && tokens[dot].text == "." && tokens[name].text == "exports" && tokens[eq].text == "=" {
+				return resolveObject(tokens, nextSignificant(tokens, eq))
+			}
+		}
+	}
+
+	return -1, fmt.Errorf("no exported config object found")
+}
+
+// resolveObject follows i to the object literal it evaluates to: a literal,
+// a parenthesized expression, an arrow or function returning a literal, or a
+// variable initialized with one.
+func resolveObject(tokens []token, i int) (int, error) {
+	if i < 0 {
+		return -1, fmt.Errorf("unexpected end of config")
+	}
+
+	tok := tokens[i]
+	switch {
+	case tok.text == "{" && tok.kind == tokPunct:
+		return i, nil
+
+	case tok.text == "(" && tok.kind == tokPunct:
+		after := nextSignificant(tokens, tok.match)
+		if after >= 0 && tokens[after].text == "=>" {
+			return resolveBody(tokens, nextSignificant(tokens, after))
+		}
+		if after >= 0 && tokens[after].text == ":" {
+			return resolveArrowWithReturnType(tokens, after)
+		}
+		return resolveObject(tokens, nextSignificant(tokens, i))
+
+	case tok.kind == tokIdent && tok.text == "async":
+		return resolveObject(tokens, nextSignificant(tokens, i))
+
+	case tok.kind == tokIdent && tok.text == "function":
+		j := nextSignificant(tokens, i)
+		for j >= 0 && tokens[j].text != "(" {
+			j = nextSignificant(tokens, j)
+		}
+		if j < 0 {
+			return -1, fmt.Errorf("malformed function config")
+		}
+		body := nextSignificant(tokens, tokens[j].match)
+		for body >= 0 && tokens[body].text != "{" {
+			body = nextSignificant(tokens, body)
+		}
+		return resolveBody(tokens, body)
+
+	case tok.kind == tokIdent:
+		after := nextSignificant(tokens, i)
+		if after >= 0 && tokens[after].text == "=>" {
+			return resolveBody(tokens, nextSignificant(tokens, after))
+		}
+		return resolveVariable(tokens, tok.text)
+	}
+
+	return -1, fmt.Errorf("unsupported config expression %q", tok.text)
+}
+
+func resolveArrowWithReturnType(tokens []token, colon int) (int, error) {
+	for j := nextSignificant(tokens, colon); j >= 0; j = nextSignificant(tokens, j) {
+		if tokens[j].text == "=>" {
+			return resolveBody(tokens, nextSignificant(tokens, j))
+		}
+		if tokens[j].match > j {
+			j = tokens[j].match
+		}
+	}
+	return -1, fmt.Errorf("malformed arrow function config")
+}
+
+func resolveBody(tokens []token, i int) (int, error) {
+	if i < 0 {
+		return -1, fmt.Errorf("unexpected end of config")
+	}
+	if tokens[i].text != "{" {
+		return resolveObject(tokens, i)
+	}
+
+	for j := nextSignificant(tokens, i); j >= 0 && j < tokens[i].match; j = nextSignificant(tokens, j) {
+		if tokens[j].kind == tokIdent && tokens[j].text == "return" {
+			return resolveObject(tokens, nextSignificant(tokens, j))
+		}
+		if tokens[j].match > j {
+			j = tokens[j].match
+		}
+	}
+
+	return -1, fmt.Errorf("config function does not return an object literal")
+}
+
+func resolveVariable(tokens []token, name string) (int, error) {
+	for i, tok := range tokens {
+		if tok.kind != tokIdent || (tok.text != "const" && tok.text != "let" && tok.text != "var") {
+			continue
+		}
+		ident := nextSignificant(tokens, i)
+		if ident < 0 || tokens[ident].text != name {
+			continue
+		}
+		for j := nextSignificant(tokens, ident); j >= 0; j = nextSignificant(tokens, j) {
+			if tokens[j].text == "=" {
+				return resolveObject(tokens, nextSignificant(tokens, j))
+			}
+			if tokens[j].text == ";" {
+				break
+			}
+		}
+	}
+
+	return -1, fmt.Errorf("could not resolve config variable %q", name)
+}
+
+type property struct {
+	key        string
+	valueStart int
+	valueEnd   int
+	// shorthand is set for `{ build }`, spread for `{ ...base }`; neither
+	// has a value to edit.
+	shorthand bool
+	spread    bool
+}
+
+// findProperty looks up the last key of the dotted name in obj. A shorthand
+// property with that key, or a spread after the place the value is written
+// (the property, or the top of obj when it is missing), could override the
+// value, so such configs are refused instead of silently not patched.
+func findProperty(tokens []token, obj int, name string) (property, bool, error) {
+	key := name[strings.LastIndexByte(name, '.')+1:]
+	props := objectProperties(tokens, obj)
+
+	found := -1
+	for i, prop := range props {
+		if prop.shorthand && prop.key == key {
+			return property{}, false, fmt.Errorf("unpatchable config: %s is a shorthand property, set it in the config by hand", name)
+		}
+		if !prop.spread && prop.key == key {
+			found = i
+		}
+	}
+
+	for _, prop := range props[found+1:] {
+		if prop.spread {
+			return property{}, false, fmt.Errorf("unpatchable config: a spread could override %s, set it in the config by hand", name)
+		}
+	}
+
+	if found < 0 {
+		return property{}, false, nil
+	}
+	return props[found], true, nil
+}
+
+func objectProperties(tokens []token, obj int) []property {
+	var props []property
+	end := tokens[obj].match
+
+	i := nextSignificant(tokens, obj)
+	for i >= 0 && i < end {
+		keyTok := tokens[i]
+		colon := nextSignificant(tokens, i)
+
+		var key string
+		switch keyTok.kind {
+		case tokIdent, tokNumber:
+			key = keyTok.text
+		case tokString:
+			key, _ = strconv.Unquote(`"` + keyTok.text[1:len(keyTok.text)-1] + `"`)
+		}
+
+		valueStart, valueEnd := -1, -1
+		if key != "" && colon >= 0 && colon < end && tokens[colon].text == ":" {
+			valueStart = nextSignificant(tokens, colon)
+			i = valueStart
+		} else if keyTok.kind == tokIdent && (colon < 0 || colon >= end || tokens[colon].text == ",") {
+			props = append(props, property{key: key, shorthand: true})
+		} else if keyTok.text == "..." {
+			props = append(props, property{spread: true})
+		}
+
+		for i >= 0 && i < end && tokens[i].text != "," {
+			if tokens[i].match > i {
+				i = tokens[i].match
+			}
+			valueEnd = i
+			i = nextSignificant(tokens, i)
+		}
+
+		if valueStart >= 0 && valueEnd >= valueStart {
+			props = append(props, property{key: key, valueStart: valueStart, valueEnd: valueEnd})
+		}
+
+		if i >= 0 && i < end {
+			i = nextSignificant(tokens, i)
+		}
+	}
+
+	return props
+}
+
+func insertProperty(src string, tokens []token, obj int, path []string, value string) string {
+	open := tokens[obj]
+	close := tokens[open.match]
+	step := detectIndentStep(src)
+	first := obj + 1
+
+	if first == open.match {
+		indent := lineIndent(src, open.start)
+		text := "\n" + formatProperty(path, value, indent+step, step) + ",\n" + indent
+		return src[:open.end] + text + src[close.start:]
+	}
+
+	if sameLine(src, open.end, tokens[first].start) {
+		return src[:open.end] + " " + formatInline(path, value) + "," + src[open.end:]
+	}
+
+	indent := lineIndent(src, tokens[first].start)
+	return src[:open.end] + "\n" + formatProperty(path, value, indent, step) + "," + src[open.end:]
+}
+
+func formatProperty(path []string, value, indent, step string) string {
+	if len(path) == 1 {
+		return indent + path[0] + ": " + value
+	}
+	return indent + path[0] + ": {\n" + formatProperty(path[1:], value, indent+step, step) + ",\n" + indent + "}"
+}
+
+func formatInline(path []string, value string) string {
+	if len(path) == 1 {
+		return path[0] + ": " + value
+	}
+	return path[0] + ": { " + formatInline(path[1:], value) + " }"
+}
+
+func sameValue(current, value string) bool {
+	if current == value {
+		return true
+	}
+	a, errA := unquoteJS(current)
+	b, errB := unquoteJS(value)
+	return errA == nil && errB == nil && a == b
+}
+
+func unquoteJS(s string) (string, error) {
+	if len(s) < 2 || (s[0] != '\'' && s[0] != '"') || s[len(s)-1] != s[0] {
+		return "", fmt.Errorf("not a string literal")
+	}
+	return s[1 : len(s)-1], nil
+}
+
+func detectQuote(src string) func(string) string {
+	single := strings.Count(src, "'")
+	double := strings.Count(src, "\"")
+	q := "'"
+	if double > single {
+		q = "\""
+	}
+	return func(s string) string {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+		return q + strings.ReplaceAll(s, q, `\`+q) + q
+	}
+}
+
+func detectIndentStep(src string) string {
+	for _, line := range strings.Split(src, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			return "\t"
+		}
+		trimmed := strings.TrimLeft(line, " ")
+		if n := len(line) - len(trimmed); n > 0 && trimmed != "" && !strings.HasPrefix(trimmed, "*") {
+			return strings.Repeat(" ", n)
+		}
+	}
+	return "  "
+}
+
+func lineIndent(src string, offset int) string {
+	lineStart := strings.LastIndexByte(src[:offset], '\n') + 1
+	line := src[lineStart:]
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+func sameLine(src string, a, b int) bool {
+	return !strings.Contains(src[a:b], "\n")
+}
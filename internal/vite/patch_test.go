@@ -0,0 +1,373 @@
+package vite
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var eelOptions = Options{
+	OutDir:      "../.distweb",
+	EmptyOutDir: true,
+	Base:        "./",
+	Plugins:     []Plugin{{Name: "eel", From: "./vite-plugin-eel.js"}},
+}
+
+func TestPatch(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		src  string
+		want string
+	}{
+		{
+			name: "defineConfig object",
+			opts: eelOptions,
+			src: `import { defineConfig } from 'vite'
+import react from '@vitejs/plugin-react'
+
+export default defineConfig({
+  plugins: [react()],
+})
+`,
+			want: `import { defineConfig } from 'vite'
+import react from '@vitejs/plugin-react'
+import eel from './vite-plugin-eel.js'
+
+export default defineConfig({
+  build: {
+    outDir: '../.distweb',
+    emptyOutDir: true,
+  },
+  base: './',
+  plugins: [react(), eel()],
+})
+`,
+		},
+		{
+			name: "arrow function returning an object",
+			opts: eelOptions,
+			src: `import { defineConfig } from "vite";
+
+export default defineConfig(({ mode }) => ({
+  server: { port: 3000 },
+}));
+`,
+			want: `import { defineConfig } from "vite";
+import eel from "./vite-plugin-eel.js";
+
+export default defineConfig(({ mode }) => ({
+  plugins: [eel()],
+  build: {
+    outDir: "../.distweb",
+    emptyOutDir: true,
+  },
+  base: "./",
+  server: { port: 3000 },
+}));
+`,
+		},
+		{
+			name: "arrow function with a block body",
+			opts: eelOptions,
+			src: `import { defineConfig } from 'vite'
+
+export default defineConfig(({ command }) => {
+  const x = { build: 1 }
+  return {
+    plugins: [],
+  }
+})
+`,
+			want: `import { defineConfig } from 'vite'
+import eel from './vite-plugin-eel.js'
+
+export default defineConfig(({ command }) => {
+  const x = { build: 1 }
+  return {
+    build: {
+      outDir: '../.distweb',
+      emptyOutDir: true,
+    },
+    base: './',
+    plugins: [eel()],
+  }
+})
+`,
+		},
+		{
+			name: "arrow function with a return type",
+			opts: Options{OutDir: "../.distweb", EmptyOutDir: true},
+			src: `export default defineConfig((): UserConfig => ({
+  base: '/',
+}))
+`,
+			want: `export default defineConfig((): UserConfig => ({
+  build: {
+    outDir: '../.distweb',
+    emptyOutDir: true,
+  },
+  base: '/',
+}))
+`,
+		},
+		{
+			name: "existing build without outDir",
+			opts: Options{OutDir: "../.distweb", EmptyOutDir: true},
+			src: `export default {
+  build: {
+    sourcemap: true,
+  },
+}
+`,
+			want: `export default {
+  build: {
+    outDir: '../.distweb',
+    emptyOutDir: true,
+    sourcemap: true,
+  },
+}
+`,
+		},
+		{
+			name: "inline build with other values",
+			opts: Options{OutDir: "../.distweb", EmptyOutDir: true, Base: "./"},
+			src: `export default {
+  base: '/',
+  build: { outDir: 'dist', emptyOutDir: false },
+}
+`,
+			want: `export default {
+  base: './',
+  build: { outDir: '../.distweb', emptyOutDir: true },
+}
+`,
+		},
+		{
+			name: "comments and strings that look like config",
+			opts: eelOptions,
+			src: `// build: { outDir: 'x' }
+/* plugins: [ */
+export default {
+  // the build
+  define: { s: '{ build: }' },
+  plugins: [
+    foo(), // trailing
+  ],
+}
+`,
+			want: `import eel from './vite-plugin-eel.js'
+// build: { outDir: 'x' }
+/* plugins: [ */
+export default {
+  build: {
+    outDir: '../.distweb',
+    emptyOutDir: true,
+  },
+  base: './',
+  // the build
+  define: { s: '{ build: }' },
+  plugins: [
+    foo(), // trailing
+    eel(),
+  ],
+}
+`,
+		},
+		{
+			name: "comment after the last plugin without a trailing comma",
+			opts: Options{OutDir: "dist", Plugins: eelOptions.Plugins},
+			src: `import vue from '@vitejs/plugin-vue';
+
+export default {
+  build: { outDir: 'dist', emptyOutDir: false },
+  plugins: [
+    vue() // the app
+  ],
+};
+`,
+			want: `import vue from '@vitejs/plugin-vue';
+import eel from './vite-plugin-eel.js';
+
+export default {
+  build: { outDir: 'dist', emptyOutDir: false },
+  plugins: [
+    vue(), // the app
+    eel()
+  ],
+};
+`,
+		},
+		{
+			name: "config in a variable",
+			opts: eelOptions,
+			src: `const config = {
+  plugins: [vue()]
+}
+
+export default config
+`,
+			want: `import eel from './vite-plugin-eel.js'
+const config = {
+  build: {
+    outDir: '../.distweb',
+    emptyOutDir: true,
+  },
+  base: './',
+  plugins: [vue(), eel()]
+}
+
+export default config
+`,
+		},
+		{
+			name: "empty object",
+			opts: eelOptions,
+			src:  "export default {}\n",
+			want: `import eel from './vite-plugin-eel.js'
+export default {
+  plugins: [eel()],
+  build: {
+    outDir: '../.distweb',
+    emptyOutDir: true,
+  },
+  base: './',
+}
+`,
+		},
+		{
+			name: "spread before the properties",
+			opts: Options{OutDir: "../.distweb", EmptyOutDir: true, Base: "./"},
+			src: `export default {
+  ...shared,
+  base: '/',
+  build: { outDir: 'dist', emptyOutDir: false },
+}
+`,
+			want: `export default {
+  ...shared,
+  base: './',
+  build: { outDir: '../.distweb', emptyOutDir: true },
+}
+`,
+		},
+		{
+			name: "default config",
+			opts: eelOptions,
+			src:  DefaultConfig,
+			want: `import { defineConfig } from 'vite'
+import eel from './vite-plugin-eel.js'
+
+export default defineConfig({
+  plugins: [eel()],
+  build: {
+    outDir: '../.distweb',
+    emptyOutDir: true,
+  },
+  base: './',
+})
+`,
+		},
+		{
+			name: "already patched",
+			opts: eelOptions,
+			src: `import eel from './vite-plugin-eel.js'
+
+export default {
+  base: './',
+  build: { outDir: '../.distweb', emptyOutDir: true },
+  plugins: [eel()],
+}
+`,
+			want: `import eel from './vite-plugin-eel.js'
+
+export default {
+  base: './',
+  build: { outDir: '../.distweb', emptyOutDir: true },
+  plugins: [eel()],
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Patch(tt.src, tt.opts)
+			if err != nil {
+				t.Fatalf("Patch: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Patch mismatch\n--- got ---\n%s\n--- want ---\n%s", got, tt.want)
+			}
+
+			again, err := Patch(got, tt.opts)
+			if err != nil {
+				t.Fatalf("second Patch: %v", err)
+			}
+			if again != got {
+				t.Errorf("Patch is not idempotent\n--- first ---\n%s\n--- second ---\n%s", got, again)
+			}
+		})
+	}
+}
+
+func TestPatchErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"unclosed brace", "export default {\n  build: {\n", "cannot parse config"},
+		{"no object literal", "export default makeConfig()\n", "cannot parse config"},
+		{"plugins not an array", "export default {\n  plugins: getPlugins(),\n}\n", "plugins is not an array literal"},
+		{"shorthand build", "const build = { outDir: 'dist' }\nexport default defineConfig({ build, })\n", "unpatchable config: build is a shorthand property"},
+		{"shorthand plugins", "export default {\n  build: { outDir: '../.distweb', emptyOutDir: true },\n  base: './',\n  plugins,\n}\n", "unpatchable config: plugins is a shorthand property"},
+		{"spread after the inserted keys", "export default {\n  plugins: [],\n  ...shared,\n}\n", "unpatchable config: a spread could override base"},
+		{"spread after a property", "export default {\n  base: '/',\n  build: { outDir: 'dist', ...extra },\n}\n", "unpatchable config: a spread could override build.emptyOutDir"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Patch(tt.src, eelOptions)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Patch error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "vite.config.js")
+
+	broken := "export default {\n  build: {\n"
+	if err := os.WriteFile(path, []byte(broken), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PatchFile(path, eelOptions); err == nil {
+		t.Fatal("PatchFile accepted a config that does not parse")
+	}
+	if data, _ := os.ReadFile(path); string(data) != broken {
+		t.Fatalf("PatchFile wrote a config it could not parse:\n%s", data)
+	}
+
+	if err := os.WriteFile(path, []byte("export default {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	diff, err := PatchFile(path, eelOptions)
+	if err != nil {
+		t.Fatalf("PatchFile: %v", err)
+	}
+	if !strings.Contains(diff, "+  base: './',") {
+		t.Errorf("diff does not show the change:\n%s", diff)
+	}
+
+	diff, err = PatchFile(path, eelOptions)
+	if err != nil {
+		t.Fatalf("second PatchFile: %v", err)
+	}
+	if diff != "" {
+		t.Errorf("second PatchFile changed the config again:\n%s", diff)
+	}
+}
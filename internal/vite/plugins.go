@@ -0,0 +1,155 @@
+package vite
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Plugin is a Vite plugin added to the config: `import <Name> from '<From>'`
+// plus a `<Name>()` entry in the plugins array.
+type Plugin struct {
+	Name string
+	From string
+}
+
+func addPlugin(src string, plugin Plugin) (string, error) {
+	src, err := ensureImport(src, plugin)
+	if err != nil {
+		return "", err
+	}
+	return ensurePluginEntry(src, plugin)
+}
+
+// ensureImport adds the default import for plugin after the last top-level
+// import, unless the module is already imported.
+func ensureImport(src string, plugin Plugin) (string, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return "", err
+	}
+
+	lastEnd := -1
+	semicolon := strings.Contains(src, ";")
+	for i := 0; i >= 0 && i < len(tokens); i = nextSignificant(tokens, i) {
+		tok := tokens[i]
+		if tok.kind == tokIdent && tok.text == "import" {
+			if next := nextSignificant(tokens, i); next >= 0 && (tokens[next].text == "(" || tokens[next].text == ".") {
+				continue
+			}
+
+			from, end := importSource(tokens, i)
+			if from < 0 {
+				continue
+			}
+			if s, err := unquoteJS(tokens[from].text); err == nil && s == plugin.From {
+				return src, nil
+			}
+
+			semicolon = tokens[end].text == ";"
+			lastEnd = tokens[end].end
+			i = end
+			continue
+		}
+		if tok.match > i {
+			i = tok.match
+		}
+	}
+
+	line := "import " + plugin.Name + " from " + detectQuote(src)(plugin.From)
+	if semicolon {
+		line += ";"
+	}
+
+	if lastEnd < 0 {
+		return line + "\n" + src, nil
+	}
+	return src[:lastEnd] + "\n" + line + src[lastEnd:], nil
+}
+
+// importSource returns the module string token of the import statement at i
+// and the last token of the statement.
+func importSource(tokens []token, i int) (int, int) {
+	for j := nextSignificant(tokens, i); j >= 0; j = nextSignificant(tokens, j) {
+		if tokens[j].kind == tokString {
+			end := j
+			if next := nextSignificant(tokens, j); next >= 0 && tokens[next].text == ";" {
+				end = next
+			}
+			return j, end
+		}
+		if tokens[j].text == ";" {
+			break
+		}
+		if tokens[j].match > j {
+			j = tokens[j].match
+		}
+	}
+	return -1, -1
+}
+
+func ensurePluginEntry(src string, plugin Plugin) (string, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return "", err
+	}
+
+	obj, err := findConfigObject(tokens)
+	if err != nil {
+		return "", err
+	}
+
+	call := plugin.Name + "()"
+
+	prop, ok, err := findProperty(tokens, obj, "plugins")
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return insertProperty(src, tokens, obj, []string{"plugins"}, "["+call+"]"), nil
+	}
+
+	open := tokens[prop.valueStart]
+	if open.text != "[" || open.match != prop.valueEnd {
+		return "", fmt.Errorf("plugins is not an array literal")
+	}
+
+	last := -1
+	for j := nextSignificant(tokens, prop.valueStart); j >= 0 && j < open.match; j = nextSignificant(tokens, j) {
+		if tokens[j].kind == tokIdent && tokens[j].text == plugin.Name {
+			if next := nextSignificant(tokens, j); next >= 0 && tokens[next].text == "(" {
+				return src, nil
+			}
+		}
+		if tokens[j].match > j {
+			j = tokens[j].match
+		}
+		last = j
+	}
+
+	if last < 0 {
+		return src[:open.end] + call + src[tokens[open.match].start:], nil
+	}
+
+	// Append after the last element, reusing a trailing comma if present.
+	lastIdx, lastTok := last, tokens[last]
+	if lastTok.text == "," {
+		last = lastSignificant(tokens, last)
+	}
+	elemEnd := tokens[last].end
+
+	if sameLine(src, open.end, tokens[open.match].start) {
+		return src[:elemEnd] + ", " + call + src[elemEnd:], nil
+	}
+
+	// The new line goes after a comment trailing the last element, so the
+	// comment stays with the element it describes.
+	indent := lineIndent(src, tokens[nextSignificant(tokens, prop.valueStart)].start)
+	lineEnd := lastTok.end
+	for k := lastIdx + 1; k < len(tokens) && tokens[k].kind == tokComment && sameLine(src, lastTok.end, tokens[k].start); k++ {
+		lineEnd = tokens[k].end
+	}
+	if lastTok.text == "," {
+		return src[:lineEnd] + "\n" + indent + call + "," + src[lineEnd:], nil
+	}
+	return src[:elemEnd] + "," + src[elemEnd:lineEnd] + "\n" + indent + call + src[lineEnd:], nil
+}
@@ -0,0 +1,297 @@
+package vite
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokNumber
+	tokString
+	tokTemplate
+	tokRegex
+	tokPunct
+	tokComment
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	start int
+	end   int
+	match int
+}
+
+type tokenizer struct {
+	src    string
+	pos    int
+	tokens []token
+	braces []bool
+}
+
+// tokenize splits JS/TS source into significant tokens. Comments are kept so
+// callers can tell where a property really ends, whitespace is dropped.
+// Brackets are matched; an unbalanced or unterminated source is an error.
+func tokenize(src string) ([]token, error) {
+	t := &tokenizer{src: src}
+	if err := t.run(); err != nil {
+		return nil, err
+	}
+	if err := matchBrackets(t.tokens); err != nil {
+		return nil, err
+	}
+	return t.tokens, nil
+}
+
+func (t *tokenizer) run() error {
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+		start := t.pos
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+			t.pos++
+
+		case strings.HasPrefix(t.src[t.pos:], "//"):
+			end := strings.IndexByte(t.src[t.pos:], '\n')
+			if end < 0 {
+				t.pos = len(t.src)
+			} else {
+				t.pos += end
+			}
+			t.emit(tokComment, start)
+
+		case strings.HasPrefix(t.src[t.pos:], "/*"):
+			end := strings.Index(t.src[t.pos+2:], "*/")
+			if end < 0 {
+				return t.errorf(start, "unterminated comment")
+			}
+			t.pos += end + 4
+			t.emit(tokComment, start)
+
+		case c == '\'' || c == '"':
+			if err := t.scanString(c); err != nil {
+				return err
+			}
+			t.emit(tokString, start)
+
+		case c == '`':
+			t.pos++
+			if err := t.scanTemplate(start); err != nil {
+				return err
+			}
+
+		case c == '}' && len(t.braces) > 0 && t.braces[len(t.braces)-1]:
+			t.braces = t.braces[:len(t.braces)-1]
+			t.pos++
+			if err := t.scanTemplate(start); err != nil {
+				return err
+			}
+
+		case c == '/' && t.regexAllowed():
+			if err := t.scanRegex(); err != nil {
+				return err
+			}
+			t.emit(tokRegex, start)
+
+		case isIdentStart(c):
+			for t.pos < len(t.src) && isIdentPart(t.src[t.pos]) {
+				t.pos++
+			}
+			t.emit(tokIdent, start)
+
+		case c >= '0' && c <= '9' || c == '.' && t.pos+1 < len(t.src) && t.src[t.pos+1] >= '0' && t.src[t.pos+1] <= '9':
+			for t.pos < len(t.src) && (isIdentPart(t.src[t.pos]) || t.src[t.pos] == '.') {
+				t.pos++
+			}
+			t.emit(tokNumber, start)
+
+		default:
+			switch {
+			case strings.HasPrefix(t.src[t.pos:], "..."):
+				t.pos += 3
+			case strings.HasPrefix(t.src[t.pos:], "=>"), strings.HasPrefix(t.src[t.pos:], "?."):
+				t.pos += 2
+			default:
+				if c == '{' {
+					t.braces = append(t.braces, false)
+				} else if c == '}' && len(t.braces) > 0 {
+					t.braces = t.braces[:len(t.braces)-1]
+				}
+				t.pos++
+			}
+			t.emit(tokPunct, start)
+		}
+	}
+
+	if len(t.braces) > 0 && t.braces[len(t.braces)-1] {
+		return t.errorf(len(t.src), "unterminated template literal")
+	}
+
+	return nil
+}
+
+func (t *tokenizer) emit(kind tokenKind, start int) {
+	t.tokens = append(t.tokens, token{
+		kind:  kind,
+		text:  t.src[start:t.pos],
+		start: start,
+		end:   t.pos,
+		match: -1,
+	})
+}
+
+func (t *tokenizer) scanString(quote byte) error {
+	start := t.pos
+	t.pos++
+	for t.pos < len(t.src) {
+		switch t.src[t.pos] {
+		case '\\':
+			t.pos += 2
+			continue
+		case '\n':
+			return t.errorf(start, "unterminated string")
+		case quote:
+			t.pos++
+			return nil
+		}
+		t.pos++
+	}
+	return t.errorf(start, "unterminated string")
+}
+
+// scanTemplate scans the rest of a template literal chunk. When it stops at
+// "${" the substitution is tokenized normally until its closing brace.
+func (t *tokenizer) scanTemplate(start int) error {
+	for t.pos < len(t.src) {
+		switch {
+		case t.src[t.pos] == '\\':
+			t.pos += 2
+		case t.src[t.pos] == '`':
+			t.pos++
+			t.emit(tokTemplate, start)
+			return nil
+		case strings.HasPrefix(t.src[t.pos:], "${"):
+			t.pos += 2
+			t.emit(tokTemplate, start)
+			t.braces = append(t.braces, true)
+			return nil
+		default:
+			t.pos++
+		}
+	}
+	return t.errorf(start, "unterminated template literal")
+}
+
+func (t *tokenizer) scanRegex() error {
+	start := t.pos
+	t.pos++
+	inClass := false
+	for t.pos < len(t.src) {
+		switch c := t.src[t.pos]; {
+		case c == '\\':
+			t.pos += 2
+			continue
+		case c == '\n':
+			return t.errorf(start, "unterminated regular expression")
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '/' && !inClass:
+			t.pos++
+			for t.pos < len(t.src) && isIdentPart(t.src[t.pos]) {
+				t.pos++
+			}
+			return nil
+		}
+		t.pos++
+	}
+	return t.errorf(start, "unterminated regular expression")
+}
+
+func (t *tokenizer) regexAllowed() bool {
+	prev := lastSignificant(t.tokens, len(t.tokens))
+	if prev < 0 {
+		return true
+	}
+
+	tok := t.tokens[prev]
+	switch tok.kind {
+	case tokNumber, tokString, tokRegex:
+		return false
+	case tokTemplate:
+		return strings.HasSuffix(tok.text, "${")
+	case tokIdent:
+		switch tok.text {
+		case "return", "typeof", "instanceof", "in", "of", "new", "delete", "void", "throw", "case", "do", "else", "yield", "await":
+			return true
+		}
+		return false
+	}
+
+	return tok.text != ")" && tok.text != "]" && tok.text != "}"
+}
+
+func (t *tokenizer) errorf(offset int, format string, args ...any) error {
+	line := strings.Count(t.src[:offset], "\n") + 1
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func matchBrackets(tokens []token) error {
+	pairs := map[string]string{")": "(", "]": "[", "}": "{"}
+
+	var stack []int
+	for i, tok := range tokens {
+		if tok.kind != tokPunct {
+			continue
+		}
+		switch tok.text {
+		case "(", "[", "{":
+			stack = append(stack, i)
+		case ")", "]", "}":
+			if len(stack) == 0 || tokens[stack[len(stack)-1]].text != pairs[tok.text] {
+				return fmt.Errorf("unexpected %q at offset %d", tok.text, tok.start)
+			}
+			open := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			tokens[open].match = i
+			tokens[i].match = open
+		}
+	}
+
+	if len(stack) > 0 {
+		return fmt.Errorf("unclosed %q at offset %d", tokens[stack[len(stack)-1]].text, tokens[stack[len(stack)-1]].start)
+	}
+
+	return nil
+}
+
+func lastSignificant(tokens []token, before int) int {
+	for i := before - 1; i >= 0; i-- {
+		if tokens[i].kind != tokComment {
+			return i
+		}
+	}
+	return -1
+}
+
+func nextSignificant(tokens []token, after int) int {
+	for i := after + 1; i < len(tokens); i++ {
+		if tokens[i].kind != tokComment {
+			return i
+		}
+	}
+	return -1
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9'
+}
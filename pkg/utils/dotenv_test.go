@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]string
+	}{
+		{"plain", "A=1\nB=two words\n", map[string]string{"A": "1", "B": "two words"}},
+		{"blank lines and comments", "\n# a comment\n  # indented\nA=1\n\n", map[string]string{"A": "1"}},
+		{"spaces around the key and value", "  A  =  1  \n", map[string]string{"A": "1"}},
+		{"export prefix", "export A=1\n", map[string]string{"A": "1"}},
+		{"empty value", "A=\nB=''\nC=\"\"\n", map[string]string{"A": "", "B": "", "C": ""}},
+		{"inline comment", "A=1 # the answer\n", map[string]string{"A": "1"}},
+		{"hash without a space is kept", "A=a#b\n", map[string]string{"A": "a#b"}},
+		{"single quotes are literal", `A='x # y \n $Z'` + "\n", map[string]string{"A": `x # y \n $Z`}},
+		{"double quotes", `A="x # y"` + "\n", map[string]string{"A": "x # y"}},
+		{"double quote escapes", `A="a\nb\tc\"d\\e"` + "\n", map[string]string{"A": "a\nb\tc\"d\\e"}},
+		{"comment after a quoted value", `A="x" # note` + "\n", map[string]string{"A": "x"}},
+		{"equals in the value", "URL=postgres://u:p@h/db?sslmode=require\n", map[string]string{"URL": "postgres://u:p@h/db?sslmode=require"}},
+		{"later lines win", "A=1\nA=2\n", map[string]string{"A": "2"}},
+		{"CRLF line endings", "A=1\r\nB=2\r\n", map[string]string{"A": "1", "B": "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDotenv(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseDotenv: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDotenv = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDotenvErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"no equals sign", "A=1\nJUSTAKEY\n", "line 2: expected KEY=VALUE"},
+		{"empty key", "=1\n", "line 1: expected KEY=VALUE"},
+		{"space in the key", "MY KEY=1\n", "line 1: expected KEY=VALUE"},
+		{"unterminated single quote", "A='abc\n", "line 1: unterminated quoted value"},
+		{"unterminated double quote", "A=\"abc\\\"\n", "line 1: unterminated quoted value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDotenv(strings.NewReader(tt.input))
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("ParseDotenv error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeEnv(t *testing.T) {
+	got := MergeEnv([]string{"PATH=/bin", "A=real"}, map[string]string{"A": "dotenv", "C": "3", "B": "2"})
+	want := []string{"PATH=/bin", "A=real", "B=2", "C=3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeEnv = %q, want %q", got, want)
+	}
+}
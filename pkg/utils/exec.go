@@ -2,31 +2,114 @@ package utils
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 type Executor struct {
 	logger *Logger
+	out    io.Writer
+	// groups starts each command in its own process group, see
+	// WithProcessGroups.
+	groups bool
 }
 
+// groupStopGrace is how long a cancelled process group gets to exit after
+// the terminate signal before it is killed.
+const groupStopGrace = 3 * time.Second
+
 func NewExecutor() *Executor {
 	return &Executor{
 		logger: NewLogger(),
 	}
 }
 
+// WithOutput returns an executor that sends command output, and its own
+// "Running:" lines, to out instead of the terminal.
+func (e *Executor) WithOutput(out io.Writer) *Executor {
+	return &Executor{
+		logger: e.logger,
+		out:    out,
+		groups: e.groups,
+	}
+}
+
+// WithProcessGroups returns an executor that starts each command in its own
+// process group and, once ctx is cancelled, stops the whole group: killing
+// only npm would leave node writing to node_modules. The commands don't get
+// the terminal's Ctrl+C, so the caller must cancel ctx on interrupt.
+func (e *Executor) WithProcessGroups() *Executor {
+	return &Executor{
+		logger: e.logger,
+		out:    e.out,
+		groups: true,
+	}
+}
+
 func (e *Executor) RunCommand(ctx context.Context, dir, name string, args ...string) error {
-	cmd := exec.CommandContext(ctx, name, args...)
+	return e.RunCommandEnv(ctx, dir, nil, name, args...)
+}
+
+// RunCommandEnv is RunCommand with an explicit environment; nil inherits the
+// current one.
+func (e *Executor) RunCommandEnv(ctx context.Context, dir string, env []string, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	return e.run(ctx, cmd, dir, env, name+" "+strings.Join(args, " "))
+}
+
+func (e *Executor) RunShell(ctx context.Context, dir, command string) error {
+	return e.RunShellEnv(ctx, dir, nil, command)
+}
+
+// RunShellEnv runs command through the system shell with env, appending args
+// as extra arguments to it. The args are passed verbatim: "$@" for sh, and
+// quoted by cmd's rules on Windows.
+func (e *Executor) RunShellEnv(ctx context.Context, dir string, env []string, command string, args ...string) error {
+	cmd, line := shellCommand(command, args)
+	return e.run(ctx, cmd, dir, env, line)
+}
+
+func (e *Executor) run(ctx context.Context, cmd *exec.Cmd, dir string, env []string, line string) error {
 	cmd.Dir = dir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Env = env
+	// Once the command exits, don't wait for grandchildren that inherited the
+	// output pipes.
+	cmd.WaitDelay = time.Second
 
-	e.logger.Info("Running: %s %s in %s", name, strings.Join(args, " "), dir)
+	if e.out != nil {
+		cmd.Stdout = e.out
+		cmd.Stderr = e.out
+		fmt.Fprintf(e.out, "Running: %s in %s\n", line, dir)
+	} else {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		e.logger.Info("Running: %s in %s", line, dir)
+	}
 
-	return cmd.Run()
+	if e.groups {
+		proc, err := StartProcess(line, cmd)
+		if err != nil {
+			return err
+		}
+		select {
+		case <-proc.Done():
+		case <-ctx.Done():
+			proc.Stop(groupStopGrace)
+		}
+		return proc.Err()
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { cmd.Process.Kill() })
+	defer stop()
+	return cmd.Wait()
 }
 
 func (e *Executor) RunCommandSilent(ctx context.Context, dir, name string, args ...string) error {
@@ -36,6 +119,14 @@ func (e *Executor) RunCommandSilent(ctx context.Context, dir, name string, args
 	return cmd.Run()
 }
 
+func (e *Executor) RunCommandOutput(ctx context.Context, dir, name string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
 func (e *Executor) CommandExists(name string) bool {
 	_, err := exec.LookPath(name)
 	return err == nil
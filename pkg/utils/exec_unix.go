@@ -0,0 +1,20 @@
+//go:build !windows
+
+package utils
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// shellCommand runs command with sh -c. sh passes the words after the
+// script name as "$@", so args need no quoting.
+func shellCommand(command string, args []string) (*exec.Cmd, string) {
+	line := "sh -c " + command
+	if len(args) > 0 {
+		command += ` "$@"`
+		line += " " + strings.Join(args, " ")
+		args = append([]string{"sh"}, args...)
+	}
+	return exec.Command("sh", append([]string{"-c", command}, args...)...), line
+}
@@ -0,0 +1,61 @@
+//go:build windows
+
+package utils
+
+import (
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// shellCommand runs command with cmd /S /C. Go would quote the command line
+// by the C runtime rules, which cmd does not follow, so it is built here:
+// /S makes cmd strip exactly the outer quotes and run the rest as typed.
+func shellCommand(command string, args []string) (*exec.Cmd, string) {
+	for _, arg := range args {
+		command += " " + quoteCmdArg(arg)
+	}
+	line := `cmd /S /C "` + command + `"`
+
+	cmd := exec.Command("cmd")
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: line}
+	return cmd, line
+}
+
+// cmdMetaChars are the characters cmd interprets outside of quotes.
+const cmdMetaChars = `()%!^"<>&|`
+
+// quoteCmdArg quotes arg for the program by the C runtime rules, then
+// escapes every cmd metacharacter with ^ so that cmd passes it through
+// whatever its own quote state is.
+func quoteCmdArg(arg string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	backslashes := 0
+	for _, r := range arg {
+		switch r {
+		case '\\':
+			backslashes++
+			continue
+		case '"':
+			// Backslashes before a quote are doubled, and the quote escaped.
+			b.WriteString(strings.Repeat(`\`, 2*backslashes+1))
+		default:
+			b.WriteString(strings.Repeat(`\`, backslashes))
+		}
+		backslashes = 0
+		b.WriteRune(r)
+	}
+	// The closing quote follows: double the trailing backslashes.
+	b.WriteString(strings.Repeat(`\`, 2*backslashes))
+	b.WriteByte('"')
+
+	var escaped strings.Builder
+	for _, r := range b.String() {
+		if strings.ContainsRune(cmdMetaChars, r) {
+			escaped.WriteByte('^')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package utils
+
+import "testing"
+
+func TestQuoteCmdArg(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"", `^"^"`},
+		{"plain", `^"plain^"`},
+		{"two words", `^"two words^"`},
+		{`say "hi"`, `^"say \^"hi\^"^"`},
+		{`C:\dir\`, `^"C:\dir\\^"`},
+		{`a\"b`, `^"a\\\^"b^"`},
+		{"a&b|c<d>e", `^"a^&b^|c^<d^>e^"`},
+		{"%PATH%!x!^", `^"^%PATH^%^!x^!^^^"`},
+		{"(x)", `^"^(x^)^"`},
+	}
+
+	for _, tt := range tests {
+		if got := quoteCmdArg(tt.arg); got != tt.want {
+			t.Errorf("quoteCmdArg(%q) = %s, want %s", tt.arg, got, tt.want)
+		}
+	}
+}
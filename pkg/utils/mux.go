@@ -0,0 +1,226 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var streamColors = []string{"\033[36m", "\033[35m", "\033[33m", "\033[32m", "\033[34m"}
+
+const colorReset = "\033[0m"
+
+const streamTailSize = 30
+
+type MuxOptions struct {
+	Only       []string
+	LogDir     string
+	Color      bool
+	Timestamps bool
+}
+
+// Multiplexer interleaves the output of several child processes line by
+// line, prefixing each line with the name of the process that printed it.
+type Multiplexer struct {
+	mu      sync.Mutex
+	out     io.Writer
+	opts    MuxOptions
+	streams []*Stream
+}
+
+type Stream struct {
+	mux     *Multiplexer
+	name    string
+	color   string
+	visible bool
+	buf     bytes.Buffer
+	logFile *os.File
+
+	tailMu sync.Mutex
+	tail   []string
+	hooks  []func(line string)
+}
+
+func NewMultiplexer(out io.Writer, opts MuxOptions) *Multiplexer {
+	return &Multiplexer{
+		out:  out,
+		opts: opts,
+	}
+}
+
+// IsTerminal reports whether f is attached to a terminal.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (m *Multiplexer) Stream(name string) (*Stream, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := &Stream{
+		mux:     m,
+		name:    name,
+		color:   streamColors[len(m.streams)%len(streamColors)],
+		visible: len(m.opts.Only) == 0,
+	}
+	for _, only := range m.opts.Only {
+		if only == name {
+			s.visible = true
+		}
+	}
+
+	if m.opts.LogDir != "" {
+		if err := os.MkdirAll(m.opts.LogDir, 0755); err != nil {
+			return nil, err
+		}
+		f, err := os.OpenFile(filepath.Join(m.opts.LogDir, name+".log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		s.logFile = f
+	}
+
+	m.streams = append(m.streams, s)
+	return s, nil
+}
+
+func (m *Multiplexer) width() int {
+	w := 0
+	for _, s := range m.streams {
+		w = max(w, len(s.name))
+	}
+	return w
+}
+
+func (m *Multiplexer) writeLine(s *Stream, line string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	if s.logFile != nil {
+		fmt.Fprintf(s.logFile, "%s %s\n", now.Format(time.RFC3339), StripANSI(line))
+	}
+
+	if !s.visible {
+		return
+	}
+
+	prefix := fmt.Sprintf("[%s]", s.name) + strings.Repeat(" ", m.width()-len(s.name))
+	if m.opts.Color {
+		prefix = s.color + prefix + colorReset
+	}
+	if m.opts.Timestamps {
+		stamp := now.Format("15:04:05")
+		if m.opts.Color {
+			stamp = "\033[2m" + stamp + colorReset
+		}
+		prefix = stamp + " " + prefix
+	}
+
+	fmt.Fprintf(m.out, "%s %s\n", prefix, line)
+}
+
+func (m *Multiplexer) Close() error {
+	var firstErr error
+	for _, s := range m.streams {
+		if err := s.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (s *Stream) Name() string {
+	return s.name
+}
+
+func (s *Stream) Write(p []byte) (int, error) {
+	s.buf.Write(p)
+
+	for {
+		idx := bytes.IndexByte(s.buf.Bytes(), '\n')
+		if idx < 0 {
+			break
+		}
+		line := string(s.buf.Next(idx + 1))
+		s.emit(strings.TrimRight(line, "\r\n"))
+	}
+
+	return len(p), nil
+}
+
+// OnLine registers a function called with every complete line (ANSI codes
+// stripped), regardless of whether the stream is shown.
+func (s *Stream) OnLine(hook func(line string)) {
+	s.tailMu.Lock()
+	defer s.tailMu.Unlock()
+	s.hooks = append(s.hooks, hook)
+}
+
+// Tail returns up to n of the most recent lines.
+func (s *Stream) Tail(n int) []string {
+	s.tailMu.Lock()
+	defer s.tailMu.Unlock()
+
+	if n > len(s.tail) {
+		n = len(s.tail)
+	}
+	return append([]string(nil), s.tail[len(s.tail)-n:]...)
+}
+
+func (s *Stream) emit(line string) {
+	s.mux.writeLine(s, line)
+
+	plain := StripANSI(line)
+
+	s.tailMu.Lock()
+	s.tail = append(s.tail, plain)
+	if len(s.tail) > streamTailSize {
+		s.tail = s.tail[len(s.tail)-streamTailSize:]
+	}
+	hooks := append([]func(string){}, s.hooks...)
+	s.tailMu.Unlock()
+
+	for _, hook := range hooks {
+		hook(plain)
+	}
+}
+
+// Close flushes a trailing partial line and closes the log file.
+func (s *Stream) Close() error {
+	if s.buf.Len() > 0 {
+		s.emit(strings.TrimRight(s.buf.String(), "\r\n"))
+		s.buf.Reset()
+	}
+
+	if s.logFile != nil {
+		err := s.logFile.Close()
+		s.logFile = nil
+		return err
+	}
+
+	return nil
+}
+
+func StripANSI(line string) string {
+	var b strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\033' && i+1 < len(line) && line[i+1] == '[' {
+			j := i + 2
+			for j < len(line) && (line[j] < '@' || line[j] > '~') {
+				j++
+			}
+			i = j
+			continue
+		}
+		b.WriteByte(line[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Process is a child started in its own process group so that stopping it
+// also stops everything it spawned (node under npm, python under uv, ...).
+type Process struct {
+	Name string
+
+	cmd  *exec.Cmd
+	done chan struct{}
+	err  error
+}
+
+func StartProcess(name string, cmd *exec.Cmd) (*Process, error) {
+	setProcessGroup(cmd)
+	if cmd.WaitDelay == 0 {
+		cmd.WaitDelay = 2 * time.Second
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	p := &Process{
+		Name: name,
+		cmd:  cmd,
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		p.err = cmd.Wait()
+		close(p.done)
+	}()
+
+	return p, nil
+}
+
+func (p *Process) Done() <-chan struct{} {
+	return p.done
+}
+
+// Err returns the exit error once Done is closed.
+func (p *Process) Err() error {
+	<-p.done
+	return p.err
+}
+
+func (p *Process) Exited() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stop asks the whole process group to terminate and kills it if it is still
+// running after grace. It returns once the process has been reaped.
+func (p *Process) Stop(grace time.Duration) {
+	if p.Exited() {
+		// The direct child is gone but grandchildren may still hold the group.
+		killProcessGroup(p.cmd)
+		return
+	}
+
+	terminateProcessGroup(p.cmd)
+
+	select {
+	case <-p.done:
+		killProcessGroup(p.cmd)
+	case <-time.After(grace):
+		p.Kill()
+	}
+}
+
+func (p *Process) Kill() {
+	killProcessGroup(p.cmd)
+	<-p.done
+}
+
+type ProcessSet struct {
+	mu    sync.Mutex
+	procs []*Process
+}
+
+func (s *ProcessSet) Add(p *Process) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.procs = append(s.procs, p)
+}
+
+func (s *ProcessSet) Remove(p *Process) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, proc := range s.procs {
+		if proc == p {
+			s.procs = append(s.procs[:i], s.procs[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *ProcessSet) snapshot() []*Process {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Process(nil), s.procs...)
+}
+
+// StopAll stops every process concurrently and waits for all of them.
+func (s *ProcessSet) StopAll(grace time.Duration) {
+	var wg sync.WaitGroup
+	for _, p := range s.snapshot() {
+		wg.Add(1)
+		go func(p *Process) {
+			defer wg.Done()
+			p.Stop(grace)
+		}(p)
+	}
+	wg.Wait()
+}
+
+func (s *ProcessSet) KillAll() {
+	for _, p := range s.snapshot() {
+		killProcessGroup(p.cmd)
+	}
+}
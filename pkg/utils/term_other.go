@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !windows
+
+package utils
+
+import (
+	"errors"
+	"os"
+)
+
+func EnableKeyInput(f *os.File) (func(), error) {
+	return nil, errors.New("key input is not supported on this platform")
+}
@@ -0,0 +1,35 @@
+//go:build windows
+
+package utils
+
+import (
+	"os"
+	"syscall"
+)
+
+const (
+	enableLineInput = 0x0002
+	enableEchoInput = 0x0004
+)
+
+var procSetConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+// EnableKeyInput switches the console to unbuffered, non-echoing input so
+// single keypresses can be read. Ctrl+C keeps working.
+func EnableKeyInput(f *os.File) (func(), error) {
+	handle := syscall.Handle(f.Fd())
+
+	var old uint32
+	if err := syscall.GetConsoleMode(handle, &old); err != nil {
+		return nil, err
+	}
+
+	raw := old &^ (enableLineInput | enableEchoInput)
+	if r, _, err := procSetConsoleMode.Call(uintptr(handle), uintptr(raw)); r == 0 {
+		return nil, err
+	}
+
+	return func() {
+		procSetConsoleMode.Call(uintptr(handle), uintptr(old))
+	}, nil
+}